@@ -13,12 +13,13 @@ import (
 )
 
 type Article struct {
-	ID          uuid.UUID `sql:"primary_key"`
-	AuthorID    *uuid.UUID
-	Slug        string
-	Title       string
-	Description string
-	Body        string
-	CreatedAt   *time.Time
-	UpdatedAt   *time.Time
+	ID           uuid.UUID `sql:"primary_key"`
+	AuthorID     *uuid.UUID
+	Slug         string
+	Title        string
+	Description  string
+	Body         string
+	CreatedAt    *time.Time
+	UpdatedAt    *time.Time
+	SearchVector *string
 }
@@ -13,12 +13,17 @@ import (
 )
 
 type Users struct {
-	ID           uuid.UUID `sql:"primary_key"`
-	Email        string
-	Username     string
-	PasswordHash string
-	Bio          *string
-	Image        *string
-	CreatedAt    *time.Time
-	UpdatedAt    *time.Time
+	ID                  uuid.UUID `sql:"primary_key"`
+	Email               string
+	Username            string
+	PasswordHash        string
+	Bio                 *string
+	Image               *string
+	CreatedAt           *time.Time
+	UpdatedAt           *time.Time
+	EmailVerifiedAt     *time.Time
+	Role                string
+	SuspendedAt         *time.Time
+	DeletionRequestedAt *time.Time
+	IsPrivate           bool
 }
@@ -17,14 +17,15 @@ type articleTable struct {
 	postgres.Table
 
 	// Columns
-	ID          postgres.ColumnString
-	AuthorID    postgres.ColumnString
-	Slug        postgres.ColumnString
-	Title       postgres.ColumnString
-	Description postgres.ColumnString
-	Body        postgres.ColumnString
-	CreatedAt   postgres.ColumnTimestampz
-	UpdatedAt   postgres.ColumnTimestampz
+	ID           postgres.ColumnString
+	AuthorID     postgres.ColumnString
+	Slug         postgres.ColumnString
+	Title        postgres.ColumnString
+	Description  postgres.ColumnString
+	Body         postgres.ColumnString
+	CreatedAt    postgres.ColumnTimestampz
+	UpdatedAt    postgres.ColumnTimestampz
+	SearchVector postgres.ColumnString
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -65,30 +66,32 @@ func newArticleTable(schemaName, tableName, alias string) *ArticleTable {
 
 func newArticleTableImpl(schemaName, tableName, alias string) articleTable {
 	var (
-		IDColumn          = postgres.StringColumn("id")
-		AuthorIDColumn    = postgres.StringColumn("author_id")
-		SlugColumn        = postgres.StringColumn("slug")
-		TitleColumn       = postgres.StringColumn("title")
-		DescriptionColumn = postgres.StringColumn("description")
-		BodyColumn        = postgres.StringColumn("body")
-		CreatedAtColumn   = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn   = postgres.TimestampzColumn("updated_at")
-		allColumns        = postgres.ColumnList{IDColumn, AuthorIDColumn, SlugColumn, TitleColumn, DescriptionColumn, BodyColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns    = postgres.ColumnList{AuthorIDColumn, SlugColumn, TitleColumn, DescriptionColumn, BodyColumn, CreatedAtColumn, UpdatedAtColumn}
+		IDColumn           = postgres.StringColumn("id")
+		AuthorIDColumn     = postgres.StringColumn("author_id")
+		SlugColumn         = postgres.StringColumn("slug")
+		TitleColumn        = postgres.StringColumn("title")
+		DescriptionColumn  = postgres.StringColumn("description")
+		BodyColumn         = postgres.StringColumn("body")
+		CreatedAtColumn    = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn    = postgres.TimestampzColumn("updated_at")
+		SearchVectorColumn = postgres.StringColumn("search_vector")
+		allColumns         = postgres.ColumnList{IDColumn, AuthorIDColumn, SlugColumn, TitleColumn, DescriptionColumn, BodyColumn, CreatedAtColumn, UpdatedAtColumn, SearchVectorColumn}
+		mutableColumns     = postgres.ColumnList{AuthorIDColumn, SlugColumn, TitleColumn, DescriptionColumn, BodyColumn, CreatedAtColumn, UpdatedAtColumn, SearchVectorColumn}
 	)
 
 	return articleTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:          IDColumn,
-		AuthorID:    AuthorIDColumn,
-		Slug:        SlugColumn,
-		Title:       TitleColumn,
-		Description: DescriptionColumn,
-		Body:        BodyColumn,
-		CreatedAt:   CreatedAtColumn,
-		UpdatedAt:   UpdatedAtColumn,
+		ID:           IDColumn,
+		AuthorID:     AuthorIDColumn,
+		Slug:         SlugColumn,
+		Title:        TitleColumn,
+		Description:  DescriptionColumn,
+		Body:         BodyColumn,
+		CreatedAt:    CreatedAtColumn,
+		UpdatedAt:    UpdatedAtColumn,
+		SearchVector: SearchVectorColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
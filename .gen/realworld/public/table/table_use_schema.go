@@ -10,12 +10,28 @@ package table
 // UseSchema sets a new schema name for all generated table SQL builder types. It is recommended to invoke
 // this method only once at the beginning of the program.
 func UseSchema(schema string) {
+	AccountLockout = AccountLockout.FromSchema(schema)
 	Article = Article.FromSchema(schema)
 	ArticleArticleTag = ArticleArticleTag.FromSchema(schema)
 	ArticleComment = ArticleComment.FromSchema(schema)
 	ArticleFavorite = ArticleFavorite.FromSchema(schema)
 	ArticleTag = ArticleTag.FromSchema(schema)
+	Block = Block.FromSchema(schema)
+	EmailVerificationToken = EmailVerificationToken.FromSchema(schema)
 	Follow = Follow.FromSchema(schema)
+	FollowRequest = FollowRequest.FromSchema(schema)
+	LoginAttempt = LoginAttempt.FromSchema(schema)
+	LoginChallenge = LoginChallenge.FromSchema(schema)
+	Mute = Mute.FromSchema(schema)
+	OidcLoginState = OidcLoginState.FromSchema(schema)
+	PasswordResetToken = PasswordResetToken.FromSchema(schema)
+	PersonalAccessToken = PersonalAccessToken.FromSchema(schema)
 	SchemaMigrations = SchemaMigrations.FromSchema(schema)
+	UserIdentity = UserIdentity.FromSchema(schema)
+	UserRecoveryCode = UserRecoveryCode.FromSchema(schema)
+	UserSession = UserSession.FromSchema(schema)
+	UserSessionRefreshToken = UserSessionRefreshToken.FromSchema(schema)
+	UserTotp = UserTotp.FromSchema(schema)
+	UsernameHistory = UsernameHistory.FromSchema(schema)
 	Users = Users.FromSchema(schema)
 }
@@ -17,14 +17,19 @@ type usersTable struct {
 	postgres.Table
 
 	// Columns
-	ID           postgres.ColumnString
-	Email        postgres.ColumnString
-	Username     postgres.ColumnString
-	PasswordHash postgres.ColumnString
-	Bio          postgres.ColumnString
-	Image        postgres.ColumnString
-	CreatedAt    postgres.ColumnTimestampz
-	UpdatedAt    postgres.ColumnTimestampz
+	ID                  postgres.ColumnString
+	Email               postgres.ColumnString
+	Username            postgres.ColumnString
+	PasswordHash        postgres.ColumnString
+	Bio                 postgres.ColumnString
+	Image               postgres.ColumnString
+	CreatedAt           postgres.ColumnTimestampz
+	UpdatedAt           postgres.ColumnTimestampz
+	EmailVerifiedAt     postgres.ColumnTimestampz
+	Role                postgres.ColumnString
+	SuspendedAt         postgres.ColumnTimestampz
+	DeletionRequestedAt postgres.ColumnTimestampz
+	IsPrivate           postgres.ColumnBool
 
 	AllColumns     postgres.ColumnList
 	MutableColumns postgres.ColumnList
@@ -65,30 +70,40 @@ func newUsersTable(schemaName, tableName, alias string) *UsersTable {
 
 func newUsersTableImpl(schemaName, tableName, alias string) usersTable {
 	var (
-		IDColumn           = postgres.StringColumn("id")
-		EmailColumn        = postgres.StringColumn("email")
-		UsernameColumn     = postgres.StringColumn("username")
-		PasswordHashColumn = postgres.StringColumn("password_hash")
-		BioColumn          = postgres.StringColumn("bio")
-		ImageColumn        = postgres.StringColumn("image")
-		CreatedAtColumn    = postgres.TimestampzColumn("created_at")
-		UpdatedAtColumn    = postgres.TimestampzColumn("updated_at")
-		allColumns         = postgres.ColumnList{IDColumn, EmailColumn, UsernameColumn, PasswordHashColumn, BioColumn, ImageColumn, CreatedAtColumn, UpdatedAtColumn}
-		mutableColumns     = postgres.ColumnList{EmailColumn, UsernameColumn, PasswordHashColumn, BioColumn, ImageColumn, CreatedAtColumn, UpdatedAtColumn}
+		IDColumn                  = postgres.StringColumn("id")
+		EmailColumn               = postgres.StringColumn("email")
+		UsernameColumn            = postgres.StringColumn("username")
+		PasswordHashColumn        = postgres.StringColumn("password_hash")
+		BioColumn                 = postgres.StringColumn("bio")
+		ImageColumn               = postgres.StringColumn("image")
+		CreatedAtColumn           = postgres.TimestampzColumn("created_at")
+		UpdatedAtColumn           = postgres.TimestampzColumn("updated_at")
+		EmailVerifiedAtColumn     = postgres.TimestampzColumn("email_verified_at")
+		RoleColumn                = postgres.StringColumn("role")
+		SuspendedAtColumn         = postgres.TimestampzColumn("suspended_at")
+		DeletionRequestedAtColumn = postgres.TimestampzColumn("deletion_requested_at")
+		IsPrivateColumn           = postgres.BoolColumn("is_private")
+		allColumns                = postgres.ColumnList{IDColumn, EmailColumn, UsernameColumn, PasswordHashColumn, BioColumn, ImageColumn, CreatedAtColumn, UpdatedAtColumn, EmailVerifiedAtColumn, RoleColumn, SuspendedAtColumn, DeletionRequestedAtColumn, IsPrivateColumn}
+		mutableColumns            = postgres.ColumnList{EmailColumn, UsernameColumn, PasswordHashColumn, BioColumn, ImageColumn, CreatedAtColumn, UpdatedAtColumn, EmailVerifiedAtColumn, RoleColumn, SuspendedAtColumn, DeletionRequestedAtColumn, IsPrivateColumn}
 	)
 
 	return usersTable{
 		Table: postgres.NewTable(schemaName, tableName, alias, allColumns...),
 
 		//Columns
-		ID:           IDColumn,
-		Email:        EmailColumn,
-		Username:     UsernameColumn,
-		PasswordHash: PasswordHashColumn,
-		Bio:          BioColumn,
-		Image:        ImageColumn,
-		CreatedAt:    CreatedAtColumn,
-		UpdatedAt:    UpdatedAtColumn,
+		ID:                  IDColumn,
+		Email:               EmailColumn,
+		Username:            UsernameColumn,
+		PasswordHash:        PasswordHashColumn,
+		Bio:                 BioColumn,
+		Image:               ImageColumn,
+		CreatedAt:           CreatedAtColumn,
+		UpdatedAt:           UpdatedAtColumn,
+		EmailVerifiedAt:     EmailVerifiedAtColumn,
+		Role:                RoleColumn,
+		SuspendedAt:         SuspendedAtColumn,
+		DeletionRequestedAt: DeletionRequestedAtColumn,
+		IsPrivate:           IsPrivateColumn,
 
 		AllColumns:     allColumns,
 		MutableColumns: mutableColumns,
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
+)
+
+const accountDeletionPurgeInterval = time.Hour
+
+type deleteCurrentUserRequest struct {
+	User deleteCurrentUserRequestUser `json:"user"`
+}
+
+type deleteCurrentUserRequestUser struct {
+	Password string `json:"password"`
+}
+
+type accountDeletionResponse struct {
+	Deletion accountDeletionResponseDeletion `json:"deletion"`
+}
+
+type accountDeletionResponseDeletion struct {
+	ScheduledAt time.Time `json:"scheduledAt"`
+}
+
+// userExport is written as a ZIP archive with a file for each field with format=zip.
+type userExport struct {
+	ExportedAt time.Time                 `json:"exportedAt"`
+	User       userExportUser            `json:"user"`
+	Articles   []userExportArticle       `json:"articles"`
+	Comments   []userExportComment       `json:"comments"`
+	Favorites  []userExportFavorite      `json:"favorites"`
+	Following  []userExportFollowProfile `json:"following"`
+	Followers  []userExportFollowProfile `json:"followers"`
+}
+
+type userExportUser struct {
+	ID            uuid.UUID  `json:"id"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"emailVerified"`
+	Username      string     `json:"username"`
+	Bio           *string    `json:"bio"`
+	Image         *string    `json:"image"`
+	Role          string     `json:"role"`
+	CreatedAt     *time.Time `json:"createdAt"`
+	UpdatedAt     *time.Time `json:"updatedAt"`
+}
+
+type userExportArticle struct {
+	Slug        string     `json:"slug"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Body        string     `json:"body"`
+	TagList     []string   `json:"tagList"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+type userExportComment struct {
+	ID          uuid.UUID  `json:"id"`
+	ArticleSlug string     `json:"articleSlug"`
+	Body        string     `json:"body"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+}
+
+type userExportFavorite struct {
+	Slug  string `json:"slug"`
+	Title string `json:"title"`
+}
+
+type userExportFollowProfile struct {
+	Username string `json:"username"`
+}
+
+func (app *application) deleteCurrentUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request deleteCurrentUserRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	isCorrectPassword, err := app.usersService.CheckPassword(ctx, user.ID, request.User.Password)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if !(*isCorrectPassword) {
+		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("Incorrect password for user %s", user.ID)})
+		return
+	}
+
+	deleteAt, err := app.usersService.RequestAccountDeletion(ctx, user.ID)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	response := accountDeletionResponse{
+		Deletion: accountDeletionResponseDeletion{
+			ScheduledAt: *deleteAt,
+		},
+	}
+
+	if err = writeJSON(w, http.StatusAccepted, response); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) exportCurrentUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+
+	if format != "json" && format != "zip" {
+		app.writeErrorResponse(ctx, w, &malformedRequest{msg: fmt.Sprintf("Query parameter 'format' must be json or zip. Received %s", format)})
+		return
+	}
+
+	export, err := app.makeUserExport(ctx, *user)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	filename := fmt.Sprintf("realworld-%s-%s.%s", user.Username, export.ExportedAt.Format("20060102"), format)
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		if err = writeJSON(w, http.StatusOK, export); err != nil {
+			app.writeErrorResponse(ctx, w, err)
+		}
+		return
+	}
+
+	files := []struct {
+		name    string
+		content any
+	}{
+		{"user.json", export.User},
+		{"articles.json", export.Articles},
+		{"comments.json", export.Comments},
+		{"favorites.json", export.Favorites},
+		{"following.json", export.Following},
+		{"followers.json", export.Followers},
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.WriteHeader(http.StatusOK)
+
+	zipWriter := zip.NewWriter(w)
+
+	for _, file := range files {
+		fileWriter, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			app.logger.ErrorContext(ctx, err.Error())
+			return
+		}
+
+		encoder := json.NewEncoder(fileWriter)
+		encoder.SetIndent("", "  ")
+
+		if err = encoder.Encode(file.content); err != nil {
+			app.logger.ErrorContext(ctx, err.Error())
+			return
+		}
+	}
+
+	// The status has already been sent, so errors can only be logged.
+	if err = zipWriter.Close(); err != nil {
+		app.logger.ErrorContext(ctx, err.Error())
+	}
+}
+
+func (app *application) makeUserExport(ctx context.Context, user model.Users) (*userExport, error) {
+	export := userExport{
+		ExportedAt: time.Now().UTC(),
+		User: userExportUser{
+			ID:            user.ID,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			Username:      user.Username,
+			Bio:           user.Bio,
+			Image:         user.Image,
+			Role:          user.Role,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+		},
+		Articles:  []userExportArticle{},
+		Comments:  []userExportComment{},
+		Favorites: []userExportFavorite{},
+		Following: []userExportFollowProfile{},
+		Followers: []userExportFollowProfile{},
+	}
+
+	articles, _, err := app.articlesService.ListArticles(ctx, services.ListArticles{AuthorIDs: &[]uuid.UUID{user.ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	articleIds := make([]uuid.UUID, len(*articles))
+	for i, article := range *articles {
+		articleIds[i] = article.ID
+	}
+
+	tagsByArticle, err := app.articlesService.ListTagsByArticle(ctx, articleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, article := range *articles {
+		tagList := make([]string, len(tagsByArticle[article.ID]))
+		for i, articleTag := range tagsByArticle[article.ID] {
+			tagList[i] = articleTag.Name
+		}
+
+		export.Articles = append(export.Articles, userExportArticle{
+			Slug:        article.Slug,
+			Title:       article.Title,
+			Description: article.Description,
+			Body:        article.Body,
+			TagList:     tagList,
+			CreatedAt:   article.CreatedAt,
+			UpdatedAt:   article.UpdatedAt,
+		})
+	}
+
+	comments, err := app.articlesService.ListComments(ctx, services.ListComments{AuthorID: &user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	commentedArticleIds := make([]uuid.UUID, len(*comments))
+	for i, comment := range *comments {
+		commentedArticleIds[i] = *comment.ArticleID
+	}
+
+	articleSlugs, err := app.articlesService.GetArticleSlugs(ctx, commentedArticleIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, comment := range *comments {
+		export.Comments = append(export.Comments, userExportComment{
+			ID:          comment.ID,
+			ArticleSlug: articleSlugs[*comment.ArticleID],
+			Body:        comment.Body,
+			CreatedAt:   comment.CreatedAt,
+			UpdatedAt:   comment.UpdatedAt,
+		})
+	}
+
+	favorites, _, err := app.articlesService.ListArticles(ctx, services.ListArticles{FavoritedByUserID: &user.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, favorite := range *favorites {
+		export.Favorites = append(export.Favorites, userExportFavorite{
+			Slug:  favorite.Slug,
+			Title: favorite.Title,
+		})
+	}
+
+	following, _, err := app.profilesService.ListFollowing(ctx, user.ID, nil, services.ListFollows{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range *following {
+		export.Following = append(export.Following, userExportFollowProfile{Username: profile.Username})
+	}
+
+	followers, _, err := app.profilesService.ListFollowers(ctx, user.ID, nil, services.ListFollows{})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range *followers {
+		export.Followers = append(export.Followers, userExportFollowProfile{Username: profile.Username})
+	}
+
+	return &export, nil
+}
+
+func (app *application) purgeDeletedAccounts(ctx context.Context) {
+	ticker := time.NewTicker(accountDeletionPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := app.usersService.PurgeDeletedAccounts(ctx); err != nil {
+			app.logger.ErrorContext(ctx, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
+)
+
+type adminUpdateUserRequest struct {
+	User adminUpdateUserRequestUser `json:"user"`
+}
+
+type adminUpdateUserRequestUser struct {
+	Email    *string `json:"email"`
+	Username *string `json:"username"`
+	Role     *string `json:"role"`
+}
+
+type adminUserResponse struct {
+	User adminUserResponseUser `json:"user"`
+}
+
+type adminUserResponseUser struct {
+	ID            uuid.UUID  `json:"id"`
+	Email         string     `json:"email"`
+	EmailVerified bool       `json:"emailVerified"`
+	Username      string     `json:"username"`
+	Bio           *string    `json:"bio"`
+	Image         *string    `json:"image"`
+	Role          string     `json:"role"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+	SuspendedAt   *time.Time `json:"suspendedAt"`
+}
+
+type multipleAdminUsersResponse struct {
+	Users      []adminUserResponseUser `json:"users"`
+	UsersCount int                     `json:"usersCount"`
+}
+
+type accountLockoutsResponse struct {
+	Lockouts []accountLockoutsResponseLockout `json:"lockouts"`
+}
+
+type accountLockoutsResponseLockout struct {
+	ID             uuid.UUID  `json:"id"`
+	FailedAttempts int32      `json:"failedAttempts"`
+	IPAddress      string     `json:"ipAddress"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	LockedUntil    time.Time  `json:"lockedUntil"`
+	UnlockedAt     *time.Time `json:"unlockedAt"`
+	UnlockedBy     *uuid.UUID `json:"unlockedBy"`
+}
+
+func newAdminUserResponseUser(user model.Users) adminUserResponseUser {
+	return adminUserResponseUser{
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		Username:      user.Username,
+		Bio:           user.Bio,
+		Image:         user.Image,
+		Role:          user.Role,
+		CreatedAt:     *user.CreatedAt,
+		UpdatedAt:     *user.UpdatedAt,
+		SuspendedAt:   user.SuspendedAt,
+	}
+}
+
+func newAdminUserResponse(user model.Users) adminUserResponse {
+	return adminUserResponse{
+		User: newAdminUserResponseUser(user),
+	}
+}
+
+func newMultipleAdminUsersResponse(users []model.Users, usersCount int) multipleAdminUsersResponse {
+	adminUserResponseUsers := make([]adminUserResponseUser, len(users))
+	for i, user := range users {
+		adminUserResponseUsers[i] = newAdminUserResponseUser(user)
+	}
+
+	return multipleAdminUsersResponse{
+		Users:      adminUserResponseUsers,
+		UsersCount: usersCount,
+	}
+}
+
+func newAccountLockoutsResponse(lockouts []model.AccountLockout) accountLockoutsResponse {
+	accountLockoutsResponseLockouts := make([]accountLockoutsResponseLockout, len(lockouts))
+	for i, lockout := range lockouts {
+		accountLockoutsResponseLockouts[i] = accountLockoutsResponseLockout{
+			ID:             lockout.ID,
+			FailedAttempts: lockout.FailedAttempts,
+			IPAddress:      lockout.IPAddress,
+			CreatedAt:      *lockout.CreatedAt,
+			LockedUntil:    lockout.LockedUntil,
+			UnlockedAt:     lockout.UnlockedAt,
+			UnlockedBy:     lockout.UnlockedBy,
+		}
+	}
+
+	return accountLockoutsResponse{
+		Lockouts: accountLockoutsResponseLockouts,
+	}
+}
+
+func (app *application) adminListUsers(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+
+	var searchQuery *string
+	searchQueryParam := query.Get("query")
+	if searchQueryParam != "" {
+		searchQuery = &searchQueryParam
+	}
+
+	limit, offset, err := app.readPagination(r, 20)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	users, usersCount, err := app.usersService.SearchUsers(ctx, services.SearchUsers{
+		Query:  searchQuery,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newMultipleAdminUsersResponse(*users, *usersCount)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) adminGetUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user, err := app.usersService.GetUserById(ctx, userId)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newAdminUserResponse(*user)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) adminUpdateUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	admin := app.contextGetUser(r)
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	var request adminUpdateUserRequest
+
+	if err = decodeJSONBody(w, r, &request); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if request.User.Role != nil && userId == admin.ID {
+		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s cannot change their own role", admin.ID)})
+		return
+	}
+
+	user, err := app.usersService.GetUserById(ctx, userId)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	previousEmail := user.Email
+
+	if request.User.Email != nil || request.User.Username != nil {
+		user, err = app.usersService.UpdateUser(ctx, userId, services.UpdateUser{Email: request.User.Email, Username: request.User.Username})
+		if err != nil {
+			app.writeErrorResponse(ctx, w, err)
+			return
+		}
+	}
+
+	if request.User.Role != nil {
+		user, err = app.usersService.UpdateUserRole(ctx, userId, *request.User.Role)
+		if err != nil {
+			app.writeErrorResponse(ctx, w, err)
+			return
+		}
+	}
+
+	if user.Email != previousEmail {
+		app.requestEmailVerification(ctx, user.ID)
+	}
+
+	if err = writeJSON(w, http.StatusOK, newAdminUserResponse(*user)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) adminSuspendUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	admin := app.contextGetUser(r)
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if userId == admin.ID {
+		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s cannot suspend themselves", admin.ID)})
+		return
+	}
+
+	user, err := app.usersService.SuspendUser(ctx, userId)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newAdminUserResponse(*user)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) adminUnsuspendUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user, err := app.usersService.UnsuspendUser(ctx, userId)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newAdminUserResponse(*user)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) adminResetPassword(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = app.usersService.ForcePasswordReset(ctx, userId); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) adminListAccountLockouts(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	lockouts, err := app.usersService.ListAccountLockouts(ctx, userId)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newAccountLockoutsResponse(*lockouts)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) adminUnlockUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	admin := app.contextGetUser(r)
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = app.usersService.UnlockAccount(ctx, userId, &admin.ID); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) adminDeleteUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	admin := app.contextGetUser(r)
+
+	userId, err := uuid.Parse(ps.ByName("id"))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if userId == admin.ID {
+		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s cannot delete themselves through the admin API", admin.ID)})
+		return
+	}
+
+	if err = app.usersService.DeleteUser(ctx, userId); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
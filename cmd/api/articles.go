@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
-	"strconv"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,6 +16,8 @@ import (
 	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
 )
 
+const unusedTagsDeletionInterval = time.Hour
+
 type createArticleRequest struct {
 	Article createArticleRequestArticle `json:"article"`
 }
@@ -29,9 +34,12 @@ type updateArticleRequest struct {
 }
 
 type updateArticleRequestArticle struct {
-	Title       *string `json:"title"`
-	Description *string `json:"description"`
-	Body        *string `json:"body"`
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Body        *string   `json:"body"`
+	TagList     *[]string `json:"tagList"`
+	AddTags     *[]string `json:"addTags"`
+	RemoveTags  *[]string `json:"removeTags"`
 }
 
 type createCommentRequest struct {
@@ -62,6 +70,18 @@ type articleResponseArticle struct {
 type multipleArticlesResponse struct {
 	Articles      []articleResponseArticle `json:"articles"`
 	ArticlesCount int                      `json:"articlesCount"`
+	NextCursor    *string                  `json:"nextCursor"`
+}
+
+type searchArticlesResponse struct {
+	Articles      []searchResponseArticle `json:"articles"`
+	ArticlesCount int                     `json:"articlesCount"`
+}
+
+type searchResponseArticle struct {
+	articleResponseArticle
+	Rank     float64 `json:"rank"`
+	Headline string  `json:"headline"`
 }
 
 type ListOfTagsResponse struct {
@@ -81,7 +101,8 @@ type commentResponseComment struct {
 }
 
 type multipleCommentsResponse struct {
-	Comments []commentResponseComment `json:"comments"`
+	Comments   []commentResponseComment `json:"comments"`
+	NextCursor *string                  `json:"nextCursor"`
 }
 
 func newArticleResponse(article model.Article, articleTags []model.ArticleTag, favorited bool, favoritesCount int, authorProfile services.Profile) articleResponse {
@@ -106,10 +127,26 @@ func newArticleResponse(article model.Article, articleTags []model.ArticleTag, f
 	}
 }
 
-func newMultipleArticlesResponse(articleResponseArticles []articleResponseArticle) multipleArticlesResponse {
+func newMultipleArticlesResponse(articleResponseArticles []articleResponseArticle, articlesCount int) multipleArticlesResponse {
 	return multipleArticlesResponse{
 		Articles:      articleResponseArticles,
-		ArticlesCount: len(articleResponseArticles),
+		ArticlesCount: articlesCount,
+	}
+}
+
+func newSearchArticlesResponse(multipleArticlesResponse multipleArticlesResponse, results []services.ArticleSearchResult) searchArticlesResponse {
+	searchResponseArticles := make([]searchResponseArticle, len(results))
+	for i, result := range results {
+		searchResponseArticles[i] = searchResponseArticle{
+			articleResponseArticle: multipleArticlesResponse.Articles[i],
+			Rank:                   result.Rank,
+			Headline:               result.Headline,
+		}
+	}
+
+	return searchArticlesResponse{
+		Articles:      searchResponseArticles,
+		ArticlesCount: multipleArticlesResponse.ArticlesCount,
 	}
 }
 
@@ -141,17 +178,107 @@ func (app *application) listArticles(w http.ResponseWriter, r *http.Request, _ h
 
 	user := app.contextGetUser(r)
 
+	listArticles, err := app.readListArticles(ctx, r, user)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	cursor, err := app.readCursor(r)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	listArticles.Cursor = cursor
+
+	articles, articlesCount, err := app.articlesService.ListArticles(ctx, *listArticles)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	multipleArticleResponse, err := app.makeMultipleArticlesResponse(ctx, user, *articles, *articlesCount)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	// Cursors only point to a place in the default order.
+	if listArticles.Sort == nil || *listArticles.Sort == services.ArticleSortNewest {
+		multipleArticleResponse.NextCursor = nextArticlesCursor(*articles, *listArticles.Limit)
+	}
+
+	if cursor != nil {
+		setCursorLink(w, r, multipleArticleResponse.NextCursor)
+	} else {
+		setPaginationLinks(w, r, *listArticles.Limit, *listArticles.Offset, *articlesCount)
+	}
+
+	if err = writeJSON(w, http.StatusOK, multipleArticleResponse); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) searchArticles(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	searchQuery := r.URL.Query().Get("q")
+	if strings.TrimSpace(searchQuery) == "" {
+		app.writeErrorResponse(ctx, w, &malformedRequest{msg: "Query parameter 'q' is required"})
+		return
+	}
+
+	listArticles, err := app.readListArticles(ctx, r, user)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	results, articlesCount, err := app.articlesService.SearchArticles(ctx, searchQuery, *listArticles)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	articles := make([]model.Article, len(*results))
+	for i, result := range *results {
+		articles[i] = result.Article
+	}
+
+	multipleArticleResponse, err := app.makeMultipleArticlesResponse(ctx, user, articles, *articlesCount)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	setPaginationLinks(w, r, *listArticles.Limit, *listArticles.Offset, *articlesCount)
+
+	if err = writeJSON(w, http.StatusOK, newSearchArticlesResponse(*multipleArticleResponse, *results)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) readListArticles(ctx context.Context, r *http.Request, user *model.Users) (*services.ListArticles, error) {
 	query := r.URL.Query()
 
+	parameterErrors := map[string][]string{}
+
 	var authorIds *[]uuid.UUID
-	authorUsername := query.Get("author")
-	if authorUsername != "" {
-		authorIds = &[]uuid.UUID{}
+	for _, authorUsername := range query["author"] {
+		if authorUsername == "" {
+			continue
+		}
 
-		author, err := app.usersService.GetUserByUsername(ctx, authorUsername)
+		author, _, err := app.usersService.GetUserByCurrentOrPreviousUsername(ctx, authorUsername)
 		if err != nil {
-			app.writeErrorResponse(ctx, w, err)
-			return
+			return nil, err
+		}
+
+		if authorIds == nil {
+			authorIds = &[]uuid.UUID{}
 		}
 
 		*authorIds = append(*authorIds, author.ID)
@@ -160,67 +287,108 @@ func (app *application) listArticles(w http.ResponseWriter, r *http.Request, _ h
 	var favoritedByUserId *uuid.UUID
 	favoritedByUsername := query.Get("favorited")
 	if favoritedByUsername != "" {
-		favoritedByUser, err := app.usersService.GetUserByUsername(ctx, favoritedByUsername)
+		favoritedByUser, _, err := app.usersService.GetUserByCurrentOrPreviousUsername(ctx, favoritedByUsername)
 		if err != nil {
-			app.writeErrorResponse(ctx, w, err)
-			return
+			return nil, err
 		}
 		favoritedByUserId = &favoritedByUser.ID
 	}
 
-	var tagName *string
-	tagParam := query.Get("tag")
-	if tagParam != "" {
-		tagName = &tagParam
+	var tagNames *[]string
+	for _, tagName := range query["tag"] {
+		if tagName == "" {
+			continue
+		}
+
+		if tagNames == nil {
+			tagNames = &[]string{}
+		}
+
+		*tagNames = append(*tagNames, tagName)
+	}
+
+	matchAllTags := false
+	switch tagMatch := query.Get("tagMatch"); tagMatch {
+	case "", "any":
+	case "all":
+		matchAllTags = true
+	default:
+		parameterErrors["tagMatch"] = append(parameterErrors["tagMatch"], fmt.Sprintf("must be any or all. Received %s", tagMatch))
 	}
 
-	limit := 20
-	limitParam := query.Get("limit")
-	if limitParam != "" {
-		limitValue, err := strconv.Atoi(limitParam)
+	readTime := func(name string) *time.Time {
+		value := query.Get(name)
+		if value == "" {
+			return nil
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
 		if err != nil {
-			app.writeErrorResponse(ctx, w, &malformedRequest{
-				msg: fmt.Sprintf("Query parameter 'limit' must be an integer. Received %s", limitParam),
-			})
-			return
+			parameterErrors[name] = append(parameterErrors[name], fmt.Sprintf("must be a date in RFC 3339 format. Received %s", value))
+			return nil
 		}
-		limit = limitValue
+
+		return &parsed
 	}
 
-	offset := 0
-	offsetParam := query.Get("offset")
-	if offsetParam != "" {
-		offsetValue, err := strconv.Atoi(offsetParam)
-		if err != nil {
-			app.writeErrorResponse(ctx, w, &malformedRequest{
-				msg: fmt.Sprintf("Query parameter 'offset' must be an integer. Received %s", offsetParam),
-			})
-			return
+	createdAfter := readTime("createdAfter")
+	createdBefore := readTime("createdBefore")
+	updatedAfter := readTime("updatedAfter")
+	updatedBefore := readTime("updatedBefore")
+
+	if createdAfter != nil && createdBefore != nil && !createdAfter.Before(*createdBefore) {
+		parameterErrors["createdBefore"] = append(parameterErrors["createdBefore"], "must be after createdAfter")
+	}
+
+	if updatedAfter != nil && updatedBefore != nil && !updatedAfter.Before(*updatedBefore) {
+		parameterErrors["updatedBefore"] = append(parameterErrors["updatedBefore"], "must be after updatedAfter")
+	}
+
+	var sort *string
+	if query.Has("sort") {
+		sortParam := query.Get("sort")
+		if slices.Contains(services.ArticleSorts, sortParam) {
+			sort = &sortParam
+		} else {
+			parameterErrors["sort"] = append(parameterErrors["sort"], fmt.Sprintf("must be one of %s. Received %s", strings.Join(services.ArticleSorts, ", "), sortParam))
 		}
-		offset = offsetValue
 	}
 
-	articles, err := app.articlesService.ListArticles(ctx, services.ListArticles{
-		AuthorIDs:         authorIds,
-		FavoritedByUserID: favoritedByUserId,
-		TagName:           tagName,
-		Limit:             &limit,
-		Offset:            &offset,
-	})
+	limit, offset, err := app.readPagination(r, 20)
 	if err != nil {
-		app.writeErrorResponse(ctx, w, err)
-		return
+		var paginationErrors *invalidParameters
+		if !errors.As(err, &paginationErrors) {
+			return nil, err
+		}
+
+		maps.Copy(parameterErrors, paginationErrors.errors)
 	}
 
-	multipleArticleResponse, err := app.makeMultipleArticlesResponse(ctx, user, *articles)
-	if err != nil {
-		app.writeErrorResponse(ctx, w, err)
-		return
+	if len(parameterErrors) > 0 {
+		return nil, &invalidParameters{msg: "Invalid query parameters", errors: parameterErrors}
 	}
 
-	if err = writeJSON(w, http.StatusOK, multipleArticleResponse); err != nil {
-		app.writeErrorResponse(ctx, w, err)
+	var viewerId *uuid.UUID
+	if user != nil {
+		viewerId = &user.ID
 	}
+
+	return &services.ListArticles{
+		AuthorIDs:         authorIds,
+		FavoritedByUserID: favoritedByUserId,
+		HidePrivate:       true,
+		MutedByUserID:     viewerId,
+		TagNames:          tagNames,
+		MatchAllTags:      matchAllTags,
+		CreatedAfter:      createdAfter,
+		CreatedBefore:     createdBefore,
+		UpdatedAfter:      updatedAfter,
+		UpdatedBefore:     updatedBefore,
+		ViewerID:          viewerId,
+		Sort:              sort,
+		Limit:             limit,
+		Offset:            offset,
+	}, nil
 }
 
 func (app *application) feedArticles(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
@@ -228,62 +396,42 @@ func (app *application) feedArticles(w http.ResponseWriter, r *http.Request, _ h
 
 	user := app.contextGetUser(r)
 
-	query := r.URL.Query()
-
-	limit := 20
-	limitParam := query.Get("limit")
-	if limitParam != "" {
-		limitValue, err := strconv.Atoi(limitParam)
-		if err != nil {
-			app.writeErrorResponse(ctx, w, &malformedRequest{
-				msg: fmt.Sprintf("Query parameter 'limit' must be an integer. Received %s", limitParam),
-			})
-			return
-		}
-		limit = limitValue
-	}
-
-	offset := 0
-	offsetParam := query.Get("offset")
-	if offsetParam != "" {
-		offsetValue, err := strconv.Atoi(offsetParam)
-		if err != nil {
-			app.writeErrorResponse(ctx, w, &malformedRequest{
-				msg: fmt.Sprintf("Query parameter 'offset' must be an integer. Received %s", offsetParam),
-			})
-			return
-		}
-		offset = offsetValue
-	}
-
-	var authorIds []uuid.UUID
-
-	followedProfiles, err := app.profilesService.ListFollowedProfiles(ctx, user.ID)
+	limit, offset, err := app.readPagination(r, 20)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	for _, followedProfile := range *followedProfiles {
-		authorIds = append(authorIds, followedProfile.UserID)
+	cursor, err := app.readCursor(r)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
 	}
 
-	articles, err := app.articlesService.ListArticles(ctx, services.ListArticles{
-		AuthorIDs: &authorIds,
-		Limit:     &limit,
-		Offset:    &offset,
+	articles, articlesCount, err := app.articlesService.Feed(ctx, user.ID, services.Feed{
+		Cursor: cursor,
+		Limit:  limit,
+		Offset: offset,
 	})
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	multipleArticleResponse, err := app.makeMultipleArticlesResponse(ctx, user, *articles)
+	multipleArticleResponse, err := app.makeMultipleArticlesResponse(ctx, user, *articles, *articlesCount)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
+	multipleArticleResponse.NextCursor = nextArticlesCursor(*articles, *limit)
+
+	if cursor != nil {
+		setCursorLink(w, r, multipleArticleResponse.NextCursor)
+	} else {
+		setPaginationLinks(w, r, *limit, *offset, *articlesCount)
+	}
+
 	if err = writeJSON(w, http.StatusOK, multipleArticleResponse); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 	}
@@ -302,6 +450,11 @@ func (app *application) getArticleBySlug(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	if err = app.checkArticleVisible(ctx, *article, user); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	articleResponse, err := app.makeArticleResponse(ctx, user, *article)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
@@ -313,9 +466,33 @@ func (app *application) getArticleBySlug(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// checkArticleVisible hides the articles of suspended and private authors as if they didn't exist.
+func (app *application) checkArticleVisible(ctx context.Context, article model.Article, viewer *model.Users) error {
+	var viewerId *uuid.UUID
+	if viewer != nil {
+		viewerId = &viewer.ID
+	}
+
+	isVisible, err := app.articlesService.IsArticleVisible(ctx, article.ID, viewerId)
+	if err != nil {
+		return err
+	}
+
+	if !*isVisible {
+		return &resourceNotFound{msg: fmt.Sprintf("Article with slug %s not found", article.Slug)}
+	}
+
+	return nil
+}
+
 func (app *application) createArticle(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeArticlesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	var request createArticleRequest
 
 	err := decodeJSONBody(w, r, &request)
@@ -346,6 +523,11 @@ func (app *application) createArticle(w http.ResponseWriter, r *http.Request, _
 func (app *application) updateArticle(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeArticlesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	var request updateArticleRequest
 
 	err := decodeJSONBody(w, r, &request)
@@ -364,12 +546,19 @@ func (app *application) updateArticle(w http.ResponseWriter, r *http.Request, ps
 		return
 	}
 
-	if *article.AuthorID != user.ID {
+	if !services.CanActOn(*user, *article.AuthorID, services.PermissionUpdateAnyArticle) {
 		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s cannot update article with slug %s", user.Username, article.Slug)})
 		return
 	}
 
-	article, err = app.articlesService.UpdateArticle(ctx, article.ID, services.UpdateArticle{Title: request.Article.Title, Description: request.Article.Description, Body: request.Article.Body})
+	article, err = app.articlesService.UpdateArticle(ctx, article.ID, services.UpdateArticle{
+		Title:       request.Article.Title,
+		Description: request.Article.Description,
+		Body:        request.Article.Body,
+		TagList:     request.Article.TagList,
+		AddTags:     request.Article.AddTags,
+		RemoveTags:  request.Article.RemoveTags,
+	})
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
@@ -389,6 +578,11 @@ func (app *application) updateArticle(w http.ResponseWriter, r *http.Request, ps
 func (app *application) deleteArticle(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeArticlesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	user := app.contextGetUser(r)
 
 	articleSlug := ps.ByName("slug")
@@ -399,7 +593,7 @@ func (app *application) deleteArticle(w http.ResponseWriter, r *http.Request, ps
 		return
 	}
 
-	if *article.AuthorID != user.ID {
+	if !services.CanActOn(*user, *article.AuthorID, services.PermissionDeleteAnyContent) {
 		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s cannot delete article with slug %s", user.Username, article.Slug)})
 		return
 	}
@@ -413,6 +607,11 @@ func (app *application) deleteArticle(w http.ResponseWriter, r *http.Request, ps
 func (app *application) addCommentToArticle(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeCommentsWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	var request createCommentRequest
 
 	err := decodeJSONBody(w, r, &request)
@@ -431,6 +630,11 @@ func (app *application) addCommentToArticle(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if err = app.checkArticleVisible(ctx, *article, user); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	comment, err := app.articlesService.CreateComment(ctx, article.ID, user.ID, request.Comment.Body)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
@@ -461,7 +665,34 @@ func (app *application) getCommentsFromArticle(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	comments, err := app.articlesService.ListComments(ctx, services.ListComments{ArticleID: &article.ID})
+	if err = app.checkArticleVisible(ctx, *article, user); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	listComments := services.ListComments{ArticleID: &article.ID}
+	if user != nil {
+		listComments.MutedByUserID = &user.ID
+	}
+
+	// Comments are all returned at once, as the spec expects, unless a limit or a cursor is given.
+	cursor, err := app.readCursor(r)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	listComments.Cursor = cursor
+
+	if r.URL.Query().Has("limit") || cursor != nil {
+		listComments.Limit, _, err = app.readPagination(r, 20)
+		if err != nil {
+			app.writeErrorResponse(ctx, w, err)
+			return
+		}
+	}
+
+	comments, err := app.articlesService.ListComments(ctx, listComments)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
@@ -473,6 +704,12 @@ func (app *application) getCommentsFromArticle(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if listComments.Limit != nil {
+		multipleCommentsResponse.NextCursor = nextCommentsCursor(*comments, *listComments.Limit)
+
+		setCursorLink(w, r, multipleCommentsResponse.NextCursor)
+	}
+
 	if err = writeJSON(w, http.StatusOK, multipleCommentsResponse); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 	}
@@ -481,6 +718,11 @@ func (app *application) getCommentsFromArticle(w http.ResponseWriter, r *http.Re
 func (app *application) deleteComment(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeCommentsWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	user := app.contextGetUser(r)
 
 	slug := ps.ByName("slug")
@@ -505,7 +747,7 @@ func (app *application) deleteComment(w http.ResponseWriter, r *http.Request, ps
 		return
 	}
 
-	if *comment.AuthorID != user.ID {
+	if !services.CanActOn(*user, *comment.AuthorID, services.PermissionDeleteAnyContent) {
 		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s cannot delete comment %s", user.ID, comment.AuthorID)})
 		return
 	}
@@ -519,6 +761,11 @@ func (app *application) deleteComment(w http.ResponseWriter, r *http.Request, ps
 func (app *application) favoriteArticle(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeArticlesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	user := app.contextGetUser(r)
 
 	articleSlug := ps.ByName("slug")
@@ -529,6 +776,11 @@ func (app *application) favoriteArticle(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
+	if err = app.checkArticleVisible(ctx, *article, user); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	if err = app.articlesService.FavoriteArticle(ctx, user.ID, article.ID); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
@@ -554,6 +806,11 @@ func (app *application) favoriteArticle(w http.ResponseWriter, r *http.Request,
 func (app *application) unfavoriteArticle(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeArticlesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	user := app.contextGetUser(r)
 
 	articleSlug := ps.ByName("slug")
@@ -564,6 +821,11 @@ func (app *application) unfavoriteArticle(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if err = app.checkArticleVisible(ctx, *article, user); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	if err = app.articlesService.UnfavoriteArticle(ctx, user.ID, article.ID); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
@@ -602,92 +864,155 @@ func (app *application) getTags(w http.ResponseWriter, r *http.Request, _ httpro
 	}
 }
 
+func (app *application) deleteUnusedTags(ctx context.Context) {
+	ticker := time.NewTicker(unusedTagsDeletionInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := app.articlesService.DeleteUnusedTags(ctx); err != nil {
+			app.logger.ErrorContext(ctx, err.Error())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (app *application) makeArticleResponse(ctx context.Context, user *model.Users, article model.Article) (*articleResponse, error) {
-	articleTags, err := app.articlesService.ListTags(ctx, services.ListTags{ArticleID: &article.ID})
+	multipleArticlesResponse, err := app.makeMultipleArticlesResponse(ctx, user, []model.Article{article}, 1)
 	if err != nil {
 		return nil, err
 	}
 
-	favorited := false
-	if user != nil {
-		isFavorite, err := app.articlesService.IsFavorite(ctx, user.ID, article.ID)
-		if err != nil {
-			return nil, err
-		}
-		favorited = *isFavorite
+	return &articleResponse{Article: multipleArticlesResponse.Articles[0]}, nil
+}
+
+func (app *application) makeMultipleArticlesResponse(ctx context.Context, user *model.Users, articles []model.Article, articlesCount int) (*multipleArticlesResponse, error) {
+	articleIds := make([]uuid.UUID, len(articles))
+	authorIds := make([]uuid.UUID, len(articles))
+	for i, article := range articles {
+		articleIds[i] = article.ID
+		authorIds[i] = *article.AuthorID
 	}
 
-	favoritesCount, err := app.articlesService.GetFavoritesCount(ctx, article.ID)
+	tagsByArticle, err := app.articlesService.ListTagsByArticle(ctx, articleIds)
 	if err != nil {
 		return nil, err
 	}
 
-	var authorProfile *services.Profile
-
+	favorites := map[uuid.UUID]bool{}
 	if user != nil {
-		authorProfile, err = app.profilesService.GetProfile(ctx, *article.AuthorID, &user.ID)
-	} else {
-		authorProfile, err = app.profilesService.GetProfile(ctx, *article.AuthorID, nil)
+		favorites, err = app.articlesService.ListFavorites(ctx, user.ID, articleIds)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	favoritesCounts, err := app.articlesService.GetFavoritesCounts(ctx, articleIds)
 	if err != nil {
 		return nil, err
 	}
 
-	articleResponse := newArticleResponse(article, *articleTags, favorited, *favoritesCount, *authorProfile)
-
-	return &articleResponse, nil
-}
+	authorProfiles, err := app.getProfilesById(ctx, authorIds, user)
+	if err != nil {
+		return nil, err
+	}
 
-func (app *application) makeMultipleArticlesResponse(ctx context.Context, user *model.Users, articles []model.Article) (*multipleArticlesResponse, error) {
 	articleResponseArticles := make([]articleResponseArticle, len(articles))
 
 	for i, article := range articles {
-		articleResponse, err := app.makeArticleResponse(ctx, user, article)
-		if err != nil {
-			return nil, err
+		authorProfile, ok := authorProfiles[*article.AuthorID]
+		if !ok {
+			return nil, fmt.Errorf("author %s of article %s not found", *article.AuthorID, article.ID)
 		}
-		articleResponseArticles[i] = articleResponse.Article
+
+		articleResponseArticles[i] = newArticleResponse(article, tagsByArticle[article.ID], favorites[article.ID], favoritesCounts[article.ID], authorProfile).Article
 	}
 
-	multipleArticleResponse := newMultipleArticlesResponse(articleResponseArticles)
+	multipleArticleResponse := newMultipleArticlesResponse(articleResponseArticles, articlesCount)
 
 	return &multipleArticleResponse, nil
 }
 
-func (app *application) makeCommentResponse(ctx context.Context, comment model.ArticleComment, user *model.Users) (*commentResponse, error) {
-	var authorProfile *services.Profile
-	var err error
+// nextArticlesCursor is nil when the page isn't full, as it's the last one.
+func nextArticlesCursor(articles []model.Article, limit int) *string {
+	if limit == 0 || len(articles) < limit {
+		return nil
+	}
 
-	if user != nil {
-		authorProfile, err = app.profilesService.GetProfile(ctx, *comment.AuthorID, &user.ID)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		authorProfile, err = app.profilesService.GetProfile(ctx, *comment.AuthorID, nil)
-		if err != nil {
-			return nil, err
-		}
+	lastArticle := articles[len(articles)-1]
+
+	nextCursor := services.NewCursor(*lastArticle.CreatedAt, lastArticle.ID).String()
+
+	return &nextCursor
+}
+
+func nextCommentsCursor(comments []model.ArticleComment, limit int) *string {
+	if limit == 0 || len(comments) < limit {
+		return nil
 	}
 
-	commentResponse := newCommentResponse(comment, *authorProfile)
+	lastComment := comments[len(comments)-1]
+
+	nextCursor := services.NewCursor(*lastComment.CreatedAt, lastComment.ID).String()
+
+	return &nextCursor
+}
 
-	return &commentResponse, nil
+func (app *application) makeCommentResponse(ctx context.Context, comment model.ArticleComment, user *model.Users) (*commentResponse, error) {
+	multipleCommentsResponse, err := app.makeMultipleCommentsResponse(ctx, []model.ArticleComment{comment}, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commentResponse{Comment: multipleCommentsResponse.Comments[0]}, nil
 }
 
 func (app *application) makeMultipleCommentsResponse(ctx context.Context, comments []model.ArticleComment, user *model.Users) (*multipleCommentsResponse, error) {
+	authorIds := make([]uuid.UUID, len(comments))
+	for i, comment := range comments {
+		authorIds[i] = *comment.AuthorID
+	}
+
+	authorProfiles, err := app.getProfilesById(ctx, authorIds, user)
+	if err != nil {
+		return nil, err
+	}
+
 	commentResponseComments := make([]commentResponseComment, len(comments))
 
 	for i, comment := range comments {
-		commentResponse, err := app.makeCommentResponse(ctx, comment, user)
-		if err != nil {
-			return nil, err
+		authorProfile, ok := authorProfiles[*comment.AuthorID]
+		if !ok {
+			return nil, fmt.Errorf("author %s of comment %s not found", *comment.AuthorID, comment.ID)
 		}
-		commentResponseComments[i] = commentResponse.Comment
+
+		commentResponseComments[i] = newCommentResponse(comment, authorProfile).Comment
 	}
 
 	multipleCommentsResponse := multipleCommentsResponse{Comments: commentResponseComments}
 
 	return &multipleCommentsResponse, nil
 }
+
+func (app *application) getProfilesById(ctx context.Context, userIds []uuid.UUID, viewer *model.Users) (map[uuid.UUID]services.Profile, error) {
+	var viewerId *uuid.UUID
+	if viewer != nil {
+		viewerId = &viewer.ID
+	}
+
+	profiles, err := app.profilesService.GetProfiles(ctx, userIds, viewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	profilesById := make(map[uuid.UUID]services.Profile, len(*profiles))
+	for _, profile := range *profiles {
+		profilesById[profile.UserID] = profile
+	}
+
+	return profilesById, nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadListArticlesParameterErrors(t *testing.T) {
+	app := &application{
+		config: &config{maxPageLimit: 100},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/articles?tagMatch=some&createdAfter=yesterday&sort=random&limit=-1&offset=first", nil)
+
+	_, err := app.readListArticles(context.Background(), r, nil)
+
+	var invalidParameters *invalidParameters
+	if !errors.As(err, &invalidParameters) {
+		t.Fatalf("got error %v, expected invalid parameters", err)
+	}
+
+	for _, parameter := range []string{"tagMatch", "createdAfter", "sort", "limit", "offset"} {
+		if len(invalidParameters.errors[parameter]) != 1 {
+			t.Errorf("got errors %v, expected one for %s", invalidParameters.errors, parameter)
+		}
+	}
+}
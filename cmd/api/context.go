@@ -13,6 +13,8 @@ const userContextKey = contextKey("user")
 
 const tokenContextKey = contextKey("token")
 
+const scopesContextKey = contextKey("scopes")
+
 func (app *application) contextSetUser(r *http.Request, user *model.Users) *http.Request {
 	ctx := context.WithValue(r.Context(), userContextKey, user)
 	return r.WithContext(ctx)
@@ -23,6 +25,11 @@ func (app *application) contextSetToken(r *http.Request, token string) *http.Req
 	return r.WithContext(ctx)
 }
 
+func (app *application) contextSetScopes(r *http.Request, scopes []string) *http.Request {
+	ctx := context.WithValue(r.Context(), scopesContextKey, scopes)
+	return r.WithContext(ctx)
+}
+
 func (app *application) contextGetUser(r *http.Request) *model.Users {
 	user, ok := r.Context().Value(userContextKey).(*model.Users)
 	if !ok {
@@ -40,3 +47,8 @@ func (app *application) contextGetToken(r *http.Request) string {
 
 	return token
 }
+
+func (app *application) contextGetScopes(r *http.Request) ([]string, bool) {
+	scopes, ok := r.Context().Value(scopesContextKey).([]string)
+	return scopes, ok
+}
@@ -8,6 +8,15 @@ func (err *forbiddenError) Error() string {
 	return err.msg
 }
 
+type invalidParameters struct {
+	msg    string
+	errors map[string][]string
+}
+
+func (err *invalidParameters) Error() string {
+	return err.msg
+}
+
 type malformedRequest struct {
 	msg string
 }
@@ -16,6 +25,23 @@ func (err *malformedRequest) Error() string {
 	return err.msg
 }
 
+// resourceNotFound hides a resource that exists from users who aren't allowed to see it.
+type resourceNotFound struct {
+	msg string
+}
+
+func (err *resourceNotFound) Error() string {
+	return err.msg
+}
+
+type unprocessableRequest struct {
+	msg string
+}
+
+func (err *unprocessableRequest) Error() string {
+	return err.msg
+}
+
 type unauthorizedError struct {
 	msg string
 }
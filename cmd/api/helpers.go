@@ -6,8 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
 )
@@ -18,9 +23,8 @@ type errorResponse struct {
 	Errors errorResponseErrors `json:"errors"`
 }
 
-type errorResponseErrors struct {
-	Body []string `json:"body"`
-}
+// errorResponseErrors has the error messages in "body", and in the name of each invalid field, if any.
+type errorResponseErrors map[string][]string
 
 func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
 	ct := r.Header.Get("Content-Type")
@@ -98,21 +102,174 @@ func writeJSON(w http.ResponseWriter, status int, data any) error {
 	return nil
 }
 
+func (app *application) clientIP(r *http.Request) string {
+	if app.config.trustProxyHeaders {
+		forwardedFor := r.Header.Values("X-Forwarded-For")
+		if len(forwardedFor) > 0 {
+			// The last address is the one added by the proxy, the others are set by the client and can be spoofed.
+			addresses := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+			if address := strings.TrimSpace(addresses[len(addresses)-1]); address != "" {
+				return address
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func (app *application) readPagination(r *http.Request, defaultLimit int) (*int, *int, error) {
+	query := r.URL.Query()
+
+	parameterErrors := map[string][]string{}
+
+	limit := min(defaultLimit, app.config.maxPageLimit)
+	limitParam := query.Get("limit")
+	if limitParam != "" {
+		limitValue, err := strconv.Atoi(limitParam)
+		if err != nil || limitValue < 0 || limitValue > app.config.maxPageLimit {
+			parameterErrors["limit"] = append(parameterErrors["limit"], fmt.Sprintf("must be an integer between 0 and %d. Received %s", app.config.maxPageLimit, limitParam))
+		}
+		limit = limitValue
+	}
+
+	offset := 0
+	offsetParam := query.Get("offset")
+	if offsetParam != "" {
+		offsetValue, err := strconv.Atoi(offsetParam)
+		if err != nil || offsetValue < 0 {
+			parameterErrors["offset"] = append(parameterErrors["offset"], fmt.Sprintf("must be a non-negative integer. Received %s", offsetParam))
+		}
+		offset = offsetValue
+	}
+
+	if len(parameterErrors) > 0 {
+		return nil, nil, &invalidParameters{msg: "Invalid query parameters", errors: parameterErrors}
+	}
+
+	return &limit, &offset, nil
+}
+
+func setPaginationLinks(w http.ResponseWriter, r *http.Request, limit int, offset int, total int) {
+	if limit == 0 {
+		return
+	}
+
+	pageLink := func(pageOffset int, rel string) string {
+		query := r.URL.Query()
+		query.Set("limit", strconv.Itoa(limit))
+		query.Set("offset", strconv.Itoa(pageOffset))
+
+		pageURL := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+
+		return fmt.Sprintf("<%s>; rel=%q", pageURL.String(), rel)
+	}
+
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = (total - 1) / limit * limit
+	}
+
+	links := []string{pageLink(0, "first")}
+
+	if offset > 0 {
+		links = append(links, pageLink(max(min(offset-limit, lastOffset), 0), "prev"))
+	}
+
+	if offset+limit < total {
+		links = append(links, pageLink(offset+limit, "next"))
+	}
+
+	links = append(links, pageLink(lastOffset, "last"))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+func (app *application) readCursor(r *http.Request) (*services.Cursor, error) {
+	query := r.URL.Query()
+
+	cursorParam := query.Get("cursor")
+	if cursorParam == "" {
+		return nil, nil
+	}
+
+	if query.Has("offset") {
+		return nil, &unprocessableRequest{msg: "Query parameters 'cursor' and 'offset' can't be used together"}
+	}
+
+	cursor, err := services.ParseCursor(cursorParam)
+	if err != nil {
+		return nil, &malformedRequest{msg: err.Error()}
+	}
+
+	return cursor, nil
+}
+
+func setCursorLink(w http.ResponseWriter, r *http.Request, nextCursor *string) {
+	if nextCursor == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	query.Set("cursor", *nextCursor)
+
+	pageURL := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=%q", pageURL.String(), "next"))
+}
+
+func setRetryAfter(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+}
+
+func (app *application) background(fn func()) {
+	app.wg.Add(1)
+
+	go func() {
+		defer app.wg.Done()
+
+		defer func() {
+			if err := recover(); err != nil {
+				app.logger.Error(fmt.Sprintf("%s", err))
+			}
+		}()
+
+		fn()
+	}()
+}
+
 func (app *application) writeErrorResponse(ctx context.Context, w http.ResponseWriter, err error) {
 	app.logger.ErrorContext(ctx, err.Error())
 
 	var msg string
 	var status int
+	var fieldErrors map[string][]string
 
+	var accountLockedError *services.AccountLockedError
 	var alreadyExistsError *services.AlreadyExistsError
 	var invalidArgumentError *services.InvalidArgumentError
 	var notFoundError *services.NotFoundError
+	var permissionDeniedError *services.PermissionDeniedError
+	var tooManyRequestsError *services.TooManyRequestsError
+	var unauthenticatedError *services.UnauthenticatedError
 
 	var forbiddenError *forbiddenError
+	var invalidParameters *invalidParameters
 	var malformedRequest *malformedRequest
+	var resourceNotFound *resourceNotFound
 	var unauthorizedError *unauthorizedError
+	var unprocessableRequest *unprocessableRequest
 
 	switch {
+	case errors.As(err, &accountLockedError):
+		msg = "Account is temporarily locked"
+		status = http.StatusLocked
+		setRetryAfter(w, accountLockedError.RetryAfter)
+
 	case errors.As(err, &alreadyExistsError):
 		msg = err.Error()
 		status = http.StatusConflict
@@ -125,6 +282,11 @@ func (app *application) writeErrorResponse(ctx context.Context, w http.ResponseW
 		msg = err.Error()
 		status = http.StatusUnprocessableEntity
 
+	case errors.As(err, &invalidParameters):
+		msg = invalidParameters.Error()
+		status = http.StatusUnprocessableEntity
+		fieldErrors = invalidParameters.errors
+
 	case errors.As(err, &malformedRequest):
 		msg = malformedRequest.Error()
 		status = http.StatusBadRequest
@@ -133,19 +295,42 @@ func (app *application) writeErrorResponse(ctx context.Context, w http.ResponseW
 		msg = notFoundError.Error()
 		status = http.StatusNotFound
 
+	case errors.As(err, &permissionDeniedError):
+		msg = "Forbidden"
+		status = http.StatusForbidden
+
+	case errors.As(err, &resourceNotFound):
+		msg = resourceNotFound.Error()
+		status = http.StatusNotFound
+
+	case errors.As(err, &tooManyRequestsError):
+		msg = "Too many requests"
+		status = http.StatusTooManyRequests
+		setRetryAfter(w, tooManyRequestsError.RetryAfter)
+
+	case errors.As(err, &unauthenticatedError):
+		msg = "Unauthorized"
+		status = http.StatusUnauthorized
+
 	case errors.As(err, &unauthorizedError):
 		msg = "Unauthorized"
 		status = http.StatusUnauthorized
 
+	case errors.As(err, &unprocessableRequest):
+		msg = unprocessableRequest.Error()
+		status = http.StatusUnprocessableEntity
+
 	default:
 		msg = "Internal server error"
 		status = http.StatusInternalServerError
 	}
 
-	if err = writeJSON(w, status, errorResponse{
-		Errors: errorResponseErrors{
-			Body: []string{msg},
-		}}); err != nil {
+	responseErrors := errorResponseErrors{"body": []string{msg}}
+	for field, errs := range fieldErrors {
+		responseErrors[field] = errs
+	}
+
+	if err = writeJSON(w, status, errorResponse{Errors: responseErrors}); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestReadPagination(t *testing.T) {
+	app := &application{
+		config: &config{maxPageLimit: 100},
+	}
+
+	testCases := []struct {
+		query          string
+		expectedLimit  int
+		expectedOffset int
+		expectError    bool
+	}{
+		{query: "", expectedLimit: 20, expectedOffset: 0},
+		{query: "limit=0&offset=40", expectedLimit: 0, expectedOffset: 40},
+		{query: "limit=100", expectedLimit: 100, expectedOffset: 0},
+		{query: "limit=101", expectError: true},
+		{query: "limit=-1", expectError: true},
+		{query: "limit=ten", expectError: true},
+		{query: "offset=-1", expectError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+testCase.query, nil)
+
+			limit, offset, err := app.readPagination(r, 20)
+
+			if testCase.expectError {
+				if err == nil {
+					t.Errorf("got limit %d and offset %d, expected an error", *limit, *offset)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *limit != testCase.expectedLimit || *offset != testCase.expectedOffset {
+				t.Errorf("got limit %d and offset %d, expected %d and %d", *limit, *offset, testCase.expectedLimit, testCase.expectedOffset)
+			}
+		})
+	}
+}
+
+func TestReadCursor(t *testing.T) {
+	app := &application{
+		config: &config{},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	testCases := []struct {
+		query          string
+		expectedStatus int
+	}{
+		{query: "cursor=invalid", expectedStatus: http.StatusBadRequest},
+		{query: "cursor=MjAyNC0wNS0xN1QxMDozMDowMFosMDAwMDAwMDAtMDAwMC0wMDAwLTAwMDAtMDAwMDAwMDAwMDAw&offset=0", expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/articles?"+testCase.query, nil)
+
+			_, err := app.readCursor(r)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			w := httptest.NewRecorder()
+
+			app.writeErrorResponse(r.Context(), w, err)
+
+			if w.Code != testCase.expectedStatus {
+				t.Errorf("got status %d, expected %d", w.Code, testCase.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestSetPaginationLinks(t *testing.T) {
+	testCases := []struct {
+		name     string
+		offset   int
+		total    int
+		expected string
+	}{
+		{
+			name:     "first page",
+			offset:   0,
+			total:    25,
+			expected: `</articles?limit=10&offset=0&tag=go>; rel="first", </articles?limit=10&offset=10&tag=go>; rel="next", </articles?limit=10&offset=20&tag=go>; rel="last"`,
+		},
+		{
+			name:     "last page",
+			offset:   20,
+			total:    25,
+			expected: `</articles?limit=10&offset=0&tag=go>; rel="first", </articles?limit=10&offset=10&tag=go>; rel="prev", </articles?limit=10&offset=20&tag=go>; rel="last"`,
+		},
+		{
+			name:     "past the end",
+			offset:   50,
+			total:    25,
+			expected: `</articles?limit=10&offset=0&tag=go>; rel="first", </articles?limit=10&offset=20&tag=go>; rel="prev", </articles?limit=10&offset=20&tag=go>; rel="last"`,
+		},
+		{
+			name:     "no items",
+			offset:   0,
+			total:    0,
+			expected: `</articles?limit=10&offset=0&tag=go>; rel="first", </articles?limit=10&offset=0&tag=go>; rel="last"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/articles?tag=go", nil)
+			w := httptest.NewRecorder()
+
+			setPaginationLinks(w, r, 10, testCase.offset, testCase.total)
+
+			if link := w.Header().Get("Link"); link != testCase.expected {
+				t.Errorf("got Link %s, expected %s", link, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
+)
+
+type jsonWebKeySetResponse struct {
+	Keys []jsonWebKeySetResponseKey `json:"keys"`
+}
+
+type jsonWebKeySetResponseKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+func newJSONWebKeySetResponse(jsonWebKeys []services.JSONWebKey) jsonWebKeySetResponse {
+	keys := make([]jsonWebKeySetResponseKey, len(jsonWebKeys))
+	for i, jsonWebKey := range jsonWebKeys {
+		keys[i] = jsonWebKeySetResponseKey{
+			KeyType:   jsonWebKey.KeyType,
+			KeyID:     jsonWebKey.KeyID,
+			Algorithm: jsonWebKey.Algorithm,
+			Use:       jsonWebKey.Use,
+			N:         jsonWebKey.N,
+			E:         jsonWebKey.E,
+			Curve:     jsonWebKey.Curve,
+			X:         jsonWebKey.X,
+		}
+	}
+
+	return jsonWebKeySetResponse{
+		Keys: keys,
+	}
+}
+
+func (app *application) getJSONWebKeySet(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	if err := writeJSON(w, http.StatusOK, newJSONWebKeySetResponse(app.usersService.JSONWebKeySet())); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
@@ -3,12 +3,15 @@ package main
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"expvar"
 	"fmt"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,7 +22,11 @@ import (
 )
 
 type config struct {
-	port int
+	maxPageLimit             int
+	oidcRedirectURL          string
+	port                     int
+	requireEmailVerification bool
+	trustProxyHeaders        bool
 }
 
 type application struct {
@@ -27,6 +34,7 @@ type application struct {
 	config          *config
 	db              *sql.DB
 	logger          *slog.Logger
+	oidcService     *services.OIDCService
 	profilesService *services.ProfilesService
 	usersService    *services.UsersService
 	wg              sync.WaitGroup
@@ -34,14 +42,42 @@ type application struct {
 
 func main() {
 
+	var err error
+
+	accountDeletionGracePeriodSeconds, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_PERIOD_SECONDS"))
+	if err != nil {
+		log.Fatal("Environment variable ACCOUNT_DELETION_GRACE_PERIOD_SECONDS is required and must be an integer")
+	}
+
+	appUrl := os.Getenv("APP_URL")
+	if appUrl == "" {
+		log.Fatal("Environment variable APP_URL is required")
+	}
+
+	emailVerificationTokenValidForSeconds, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_TOKEN_VALID_FOR_SECONDS"))
+	if err != nil {
+		log.Fatal("Environment variable EMAIL_VERIFICATION_TOKEN_VALID_FOR_SECONDS is required and must be an integer")
+	}
+
 	jwtIss := os.Getenv("JWT_ISS")
 	if jwtIss == "" {
 		log.Fatal("Environment variable JWT_ISS is required")
 	}
 
-	jwtKey := os.Getenv("JWT_KEY")
-	if jwtKey == "" {
-		log.Fatal("Environment variable JWT_KEY is required")
+	var jwtKeys []services.JWTKey
+
+	if jwtKeysConfig := os.Getenv("JWT_KEYS"); jwtKeysConfig != "" {
+		jwtKeys, err = loadJWTKeys(jwtKeysConfig)
+		if err != nil {
+			log.Fatalf("Environment variable JWT_KEYS is invalid: %s", err.Error())
+		}
+	} else {
+		jwtKey := os.Getenv("JWT_KEY")
+		if jwtKey == "" {
+			log.Fatal("Environment variable JWT_KEY is required when JWT_KEYS is not set")
+		}
+
+		jwtKeys = []services.JWTKey{services.NewHMACJWTKey("default", []byte(jwtKey), time.Time{})}
 	}
 
 	jwtValidForSeconds, err := strconv.Atoi(os.Getenv("JWT_VALID_FOR_SECONDS"))
@@ -49,11 +85,125 @@ func main() {
 		log.Fatal("Environment variable JWT_VALID_FOR_SECONDS is required and must be an integer")
 	}
 
+	refreshTokenValidForSeconds, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_VALID_FOR_SECONDS"))
+	if err != nil {
+		log.Fatal("Environment variable REFRESH_TOKEN_VALID_FOR_SECONDS is required and must be an integer")
+	}
+
+	mailFrom := os.Getenv("MAIL_FROM")
+	if mailFrom == "" {
+		log.Fatal("Environment variable MAIL_FROM is required")
+	}
+
+	var mailer services.Mailer
+
+	switch os.Getenv("MAILER") {
+	case "smtp":
+		smtpHost := os.Getenv("SMTP_HOST")
+		if smtpHost == "" {
+			log.Fatal("Environment variable SMTP_HOST is required when MAILER is smtp")
+		}
+
+		smtpPort, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			log.Fatal("Environment variable SMTP_PORT is required when MAILER is smtp and must be an integer")
+		}
+
+		smtpMailer := services.NewSMTPMailer(smtpHost, smtpPort, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), mailFrom)
+		mailer = &smtpMailer
+	case "file":
+		mailerFile := os.Getenv("MAILER_FILE")
+		if mailerFile == "" {
+			log.Fatal("Environment variable MAILER_FILE is required when MAILER is file")
+		}
+
+		file, err := os.OpenFile(mailerFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		defer file.Close()
+
+		mailer = services.NewWriterMailer(file, mailFrom)
+	case "stdout":
+		mailer = services.NewWriterMailer(os.Stdout, mailFrom)
+	default:
+		log.Fatal("Environment variable MAILER is required and must be one of smtp, file or stdout")
+	}
+
+	maxPageLimit, err := strconv.Atoi(os.Getenv("MAX_PAGE_LIMIT"))
+	if err != nil || maxPageLimit < 1 {
+		log.Fatal("Environment variable MAX_PAGE_LIMIT is required and must be a positive integer")
+	}
+
+	// OpenID Connect login is enabled by setting OIDC_ISSUER.
+	var oidcServiceConfig *services.OIDCServiceConfig
+
+	if oidcIssuer := os.Getenv("OIDC_ISSUER"); oidcIssuer != "" {
+		oidcClientId := os.Getenv("OIDC_CLIENT_ID")
+		if oidcClientId == "" {
+			log.Fatal("Environment variable OIDC_CLIENT_ID is required when OIDC_ISSUER is set")
+		}
+
+		oidcClientSecret := os.Getenv("OIDC_CLIENT_SECRET")
+		if oidcClientSecret == "" {
+			log.Fatal("Environment variable OIDC_CLIENT_SECRET is required when OIDC_ISSUER is set")
+		}
+
+		oidcRedirectURL := os.Getenv("OIDC_REDIRECT_URL")
+		if oidcRedirectURL == "" {
+			log.Fatal("Environment variable OIDC_REDIRECT_URL is required when OIDC_ISSUER is set")
+		}
+
+		oidcServiceConfig = &services.OIDCServiceConfig{
+			Issuer:       oidcIssuer,
+			ClientID:     oidcClientId,
+			ClientSecret: oidcClientSecret,
+			RedirectURL:  oidcRedirectURL,
+		}
+	}
+
+	passwordResetTokenValidForSeconds, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TOKEN_VALID_FOR_SECONDS"))
+	if err != nil {
+		log.Fatal("Environment variable PASSWORD_RESET_TOKEN_VALID_FOR_SECONDS is required and must be an integer")
+	}
+
+	totpEncryptionKey, err := base64.StdEncoding.DecodeString(os.Getenv("TOTP_ENCRYPTION_KEY"))
+	if err != nil || len(totpEncryptionKey) != 32 {
+		log.Fatal("Environment variable TOTP_ENCRYPTION_KEY is required and must be a base64 encoded 32-byte key")
+	}
+
+	totpIssuer := os.Getenv("TOTP_ISSUER")
+	if totpIssuer == "" {
+		log.Fatal("Environment variable TOTP_ISSUER is required")
+	}
+
 	port, err := strconv.Atoi(os.Getenv("PORT"))
 	if err != nil {
 		log.Fatal("Environment variable PORT is required and must be an integer")
 	}
 
+	requireEmailVerification, err := strconv.ParseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION"))
+	if err != nil {
+		log.Fatal("Environment variable REQUIRE_EMAIL_VERIFICATION is required and must be a boolean")
+	}
+
+	reservedUsernamesConfig := os.Getenv("RESERVED_USERNAMES")
+	if reservedUsernamesConfig == "" {
+		log.Fatal("Environment variable RESERVED_USERNAMES is required")
+	}
+
+	reservedUsernames := strings.Split(reservedUsernamesConfig, ",")
+
+	trustProxyHeaders, err := strconv.ParseBool(os.Getenv("TRUST_PROXY_HEADERS"))
+	if err != nil {
+		log.Fatal("Environment variable TRUST_PROXY_HEADERS is required and must be a boolean")
+	}
+
+	usernameReuseCooldownSeconds, err := strconv.Atoi(os.Getenv("USERNAME_REUSE_COOLDOWN_SECONDS"))
+	if err != nil {
+		log.Fatal("Environment variable USERNAME_REUSE_COOLDOWN_SECONDS is required and must be an integer")
+	}
+
 	postgresDB := os.Getenv("POSTGRES_DB")
 	if postgresDB == "" {
 		log.Fatal("Environment variable POSTGRES_DB is required")
@@ -105,24 +255,51 @@ func main() {
 	}))
 
 	config := &config{
-		port: port,
+		maxPageLimit:             maxPageLimit,
+		port:                     port,
+		requireEmailVerification: requireEmailVerification,
+		trustProxyHeaders:        trustProxyHeaders,
 	}
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
-	usersServiceJWT := services.NewUsersServiceJWT(jwtIss, []byte(jwtKey), jwtValidForSeconds)
+	usersServiceJWT, err := services.NewUsersServiceJWT(jwtIss, jwtKeys, jwtValidForSeconds, refreshTokenValidForSeconds)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
-	usersService := services.NewUsersService(db, &usersServiceJWT, logger)
+	passwordHasher := services.NewArgon2idPasswordHasher(services.DefaultArgon2idParams)
+
+	usersService := services.NewUsersService(db, &usersServiceJWT, mailer, &passwordHasher, services.UsersServiceConfig{
+		AccountDeletionGracePeriodSeconds:     accountDeletionGracePeriodSeconds,
+		AppURL:                                appUrl,
+		EmailVerificationTokenValidForSeconds: emailVerificationTokenValidForSeconds,
+		PasswordResetTokenValidForSeconds:     passwordResetTokenValidForSeconds,
+		ReservedUsernames:                     reservedUsernames,
+		TOTPEncryptionKey:                     totpEncryptionKey,
+		TOTPIssuer:                            totpIssuer,
+		UsernameReuseCooldownSeconds:          usernameReuseCooldownSeconds,
+	}, logger)
 
 	profilesService := services.NewProfilesService(db, logger, &usersService)
 
 	articlesService := services.NewArticlesService(db, logger, &usersService)
 
+	var oidcService *services.OIDCService
+
+	if oidcServiceConfig != nil {
+		config.oidcRedirectURL = oidcServiceConfig.RedirectURL
+
+		oidcServiceValue := services.NewOIDCService(db, &usersService, &http.Client{Timeout: 10 * time.Second}, *oidcServiceConfig, logger)
+		oidcService = &oidcServiceValue
+	}
+
 	app := &application{
 		articlesService: &articlesService,
 		db:              db,
 		config:          config,
 		logger:          logger,
+		oidcService:     oidcService,
 		profilesService: &profilesService,
 		usersService:    &usersService,
 	}
@@ -144,3 +321,35 @@ func openDB(ctx context.Context, dsn string) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// loadJWTKeys parses a comma separated list of kid=path[@activeFrom] entries.
+func loadJWTKeys(jwtKeysConfig string) ([]services.JWTKey, error) {
+	var jwtKeys []services.JWTKey
+
+	for _, jwtKeyConfig := range strings.Split(jwtKeysConfig, ",") {
+		keyId, keyPathAndActiveFrom, found := strings.Cut(strings.TrimSpace(jwtKeyConfig), "=")
+		if !found || keyId == "" {
+			return nil, fmt.Errorf("key %q must be in the format kid=path[@activeFrom]", jwtKeyConfig)
+		}
+
+		keyPath, activeFromString, hasActiveFrom := strings.Cut(keyPathAndActiveFrom, "@")
+
+		var activeFrom time.Time
+		if hasActiveFrom {
+			var err error
+			activeFrom, err = time.Parse(time.RFC3339, activeFromString)
+			if err != nil {
+				return nil, fmt.Errorf("key %s has an invalid activation time: %w", keyId, err)
+			}
+		}
+
+		jwtKey, err := services.LoadJWTKey(keyId, keyPath, activeFrom)
+		if err != nil {
+			return nil, err
+		}
+
+		jwtKeys = append(jwtKeys, *jwtKey)
+	}
+
+	return jwtKeys, nil
+}
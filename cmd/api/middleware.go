@@ -3,9 +3,11 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
 )
 
 func (app *application) recoverPanic(handler http.Handler) http.Handler {
@@ -50,21 +52,106 @@ func (app *application) authenticateOptional(h httprouter.Handle) httprouter.Han
 	}
 }
 
+// requireSession keeps personal access tokens from managing the account they belong to.
+func (app *application) requireSession(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx := r.Context()
+
+		if _, ok := app.contextGetScopes(r); ok {
+			user := app.contextGetUser(r)
+			app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s must log in to %s %s", user.ID, r.Method, r.URL.Path)})
+			return
+		}
+
+		h(w, r, ps)
+	}
+}
+
+func (app *application) requireAdmin(h httprouter.Handle) httprouter.Handle {
+	return app.authenticate(app.requireSession(app.requirePermission(services.PermissionManageUsers, h)))
+}
+
+func (app *application) requirePermission(permission services.Permission, h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx := r.Context()
+
+		user := app.contextGetUser(r)
+
+		if !services.HasPermission(*user, permission) {
+			app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s lacks permission %s", user.ID, permission)})
+			return
+		}
+
+		h(w, r, ps)
+	}
+}
+
+func (app *application) requireVerifiedEmail(h httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		ctx := r.Context()
+
+		user := app.contextGetUser(r)
+
+		if app.config.requireEmailVerification && user.EmailVerifiedAt == nil {
+			app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s must verify their email", user.ID)})
+			return
+		}
+
+		h(w, r, ps)
+	}
+}
+
 func (app *application) serveHTTPAuthenticated(h httprouter.Handle, w http.ResponseWriter, r *http.Request, ps httprouter.Params, token string) {
 	ctx := r.Context()
 
+	if services.IsPersonalAccessToken(token) {
+		user, scopes, err := app.usersService.GetUserByPersonalAccessToken(ctx, token)
+		if err != nil {
+			app.writeErrorResponse(ctx, w, &unauthorizedError{msg: err.Error()})
+			return
+		}
+
+		if user.SuspendedAt != nil {
+			app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s is suspended", user.ID)})
+			return
+		}
+
+		r = app.contextSetUser(r, user)
+		r = app.contextSetToken(r, token)
+		r = app.contextSetScopes(r, *scopes)
+
+		h(w, r, ps)
+		return
+	}
+
 	user, err := app.usersService.GetUserByToken(ctx, token)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, &unauthorizedError{msg: err.Error()})
 		return
 	}
 
+	if user.SuspendedAt != nil {
+		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s is suspended", user.ID)})
+		return
+	}
+
 	r = app.contextSetUser(r, user)
 	r = app.contextSetToken(r, token)
 
 	h(w, r, ps)
 }
 
+func (app *application) checkScope(r *http.Request, scope string) error {
+	scopes, ok := app.contextGetScopes(r)
+	if !ok || slices.Contains(scopes, scope) {
+		return nil
+	}
+
+	user := app.contextGetUser(r)
+
+	return &forbiddenError{msg: fmt.Sprintf("Personal access token of user %s lacks scope %s", user.ID, scope)}
+}
+
 func getToken(r *http.Request) string {
 	authorizationHeader := r.Header.Get("Authorization")
 	if authorizationHeader == "" {
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+// oidcStateCookieName binds a login to the browser that started it, so an attacker can't make a user complete theirs.
+const oidcStateCookieName = "oidc_state"
+
+const oidcCookiePath = "/auth/oidc"
+
+func (app *application) startOIDCLogin(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	oidcLogin, err := app.oidcService.StartLogin(ctx)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Value:    oidcLogin.State,
+		Path:     oidcCookiePath,
+		Expires:  oidcLogin.ExpiresAt,
+		Secure:   strings.HasPrefix(app.config.oidcRedirectURL, "https://"),
+		HttpOnly: true,
+		// The identity provider redirects back with a top-level navigation, which Lax cookies are sent with.
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, oidcLogin.URL, http.StatusFound)
+}
+
+func (app *application) completeOIDCLogin(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	query := r.URL.Query()
+
+	if oidcError := query.Get("error"); oidcError != "" {
+		app.writeErrorResponse(ctx, w, &unauthorizedError{msg: fmt.Sprintf("OIDC login failed: %s %s", oidcError, query.Get("error_description"))})
+		return
+	}
+
+	state := query.Get("state")
+	code := query.Get("code")
+
+	if state == "" || code == "" {
+		app.writeErrorResponse(ctx, w, &malformedRequest{msg: "Query parameters 'state' and 'code' are required"})
+		return
+	}
+
+	stateCookie, err := r.Cookie(oidcStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(stateCookie.Value), []byte(state)) != 1 {
+		app.writeErrorResponse(ctx, w, &unauthorizedError{msg: "OIDC login state doesn't match this browser"})
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookieName,
+		Path:     oidcCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+
+	user, err := app.oidcService.CompleteLogin(ctx, state, code)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	// The identity provider only replaces the password.
+	app.writeLoginResponse(w, r, user)
+}
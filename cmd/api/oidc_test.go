@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompleteOIDCLoginStateMismatch(t *testing.T) {
+	app := &application{
+		config: &config{},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	testCases := []struct {
+		name   string
+		cookie *http.Cookie
+	}{
+		{name: "no state cookie"},
+		{name: "other state cookie", cookie: &http.Cookie{Name: oidcStateCookieName, Value: "other-state"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?state=state&code=code", nil)
+			if testCase.cookie != nil {
+				r.AddCookie(testCase.cookie)
+			}
+
+			w := httptest.NewRecorder()
+
+			app.completeOIDCLogin(w, r, nil)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("got status %d, expected %d", w.Code, http.StatusUnauthorized)
+			}
+		})
+	}
+}
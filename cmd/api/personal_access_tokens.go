@@ -0,0 +1,127 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
+)
+
+type createPersonalAccessTokenRequest struct {
+	Token createPersonalAccessTokenRequestToken `json:"token"`
+}
+
+type createPersonalAccessTokenRequestToken struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+type personalAccessTokenResponse struct {
+	Token personalAccessTokenResponseToken `json:"token"`
+}
+
+type personalAccessTokenResponseToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Token      *string    `json:"token,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type multiplePersonalAccessTokensResponse struct {
+	Tokens []personalAccessTokenResponseToken `json:"tokens"`
+}
+
+func newPersonalAccessTokenResponseToken(personalAccessToken model.PersonalAccessToken, token *string) personalAccessTokenResponseToken {
+	return personalAccessTokenResponseToken{
+		ID:         personalAccessToken.ID,
+		Name:       personalAccessToken.Name,
+		Token:      token,
+		Scopes:     services.PersonalAccessTokenScopes(personalAccessToken),
+		CreatedAt:  *personalAccessToken.CreatedAt,
+		ExpiresAt:  personalAccessToken.ExpiresAt,
+		LastUsedAt: personalAccessToken.LastUsedAt,
+	}
+}
+
+func newMultiplePersonalAccessTokensResponse(personalAccessTokens []model.PersonalAccessToken) multiplePersonalAccessTokensResponse {
+	tokens := make([]personalAccessTokenResponseToken, len(personalAccessTokens))
+	for i, personalAccessToken := range personalAccessTokens {
+		tokens[i] = newPersonalAccessTokenResponseToken(personalAccessToken, nil)
+	}
+
+	return multiplePersonalAccessTokensResponse{
+		Tokens: tokens,
+	}
+}
+
+func (app *application) createPersonalAccessToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	var request createPersonalAccessTokenRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	personalAccessToken, token, err := app.usersService.CreatePersonalAccessToken(ctx, user.ID, services.NewCreatePersonalAccessToken(request.Token.Name, request.Token.Scopes, request.Token.ExpiresAt))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	response := personalAccessTokenResponse{
+		Token: newPersonalAccessTokenResponseToken(*personalAccessToken, token),
+	}
+
+	if err = writeJSON(w, http.StatusCreated, response); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) listPersonalAccessTokens(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	personalAccessTokens, err := app.usersService.ListPersonalAccessTokens(ctx, user.ID)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newMultiplePersonalAccessTokensResponse(*personalAccessTokens)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) revokePersonalAccessToken(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	personalAccessTokenIdString := ps.ByName("id")
+
+	personalAccessTokenId, err := uuid.Parse(personalAccessTokenIdString)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = app.usersService.RevokePersonalAccessToken(ctx, user.ID, personalAccessTokenId); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
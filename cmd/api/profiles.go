@@ -1,22 +1,38 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
 	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
 )
 
+const maxSuggestedProfiles = 50
+
 type profileResponse struct {
 	Profile profileResponseProfile `json:"profile"`
 }
 
 type profileResponseProfile struct {
-	Username  string  `json:"username"`
-	Bio       *string `json:"bio"`
-	Image     *string `json:"image"`
-	Following bool    `json:"following"`
+	Username        string  `json:"username"`
+	Bio             *string `json:"bio"`
+	Image           *string `json:"image"`
+	IsPrivate       bool    `json:"isPrivate"`
+	Following       bool    `json:"following"`
+	FollowRequested bool    `json:"followRequested"`
+	FollowersCount  int     `json:"followersCount"`
+	FollowingCount  int     `json:"followingCount"`
+}
+
+type multipleProfilesResponse struct {
+	Profiles      []profileResponseProfile `json:"profiles"`
+	ProfilesCount int                      `json:"profilesCount"`
 }
 
 func newProfileResponse(profile services.Profile) profileResponse {
@@ -27,10 +43,26 @@ func newProfileResponse(profile services.Profile) profileResponse {
 
 func newProfileResponseProfile(profile services.Profile) profileResponseProfile {
 	return profileResponseProfile{
-		Username:  profile.Username,
-		Bio:       profile.Bio,
-		Image:     profile.Image,
-		Following: profile.Following,
+		Username:        profile.Username,
+		Bio:             profile.Bio,
+		Image:           profile.Image,
+		IsPrivate:       profile.Private,
+		Following:       profile.Following,
+		FollowRequested: profile.FollowRequested,
+		FollowersCount:  profile.FollowersCount,
+		FollowingCount:  profile.FollowingCount,
+	}
+}
+
+func newMultipleProfilesResponse(profiles []services.Profile, profilesCount int) multipleProfilesResponse {
+	profileResponseProfiles := make([]profileResponseProfile, len(profiles))
+	for i, profile := range profiles {
+		profileResponseProfiles[i] = newProfileResponseProfile(profile)
+	}
+
+	return multipleProfilesResponse{
+		Profiles:      profileResponseProfiles,
+		ProfilesCount: profilesCount,
 	}
 }
 
@@ -39,7 +71,7 @@ func (app *application) getProfile(w http.ResponseWriter, r *http.Request, ps ht
 
 	username := ps.ByName("username")
 
-	user, err := app.usersService.GetUserByUsername(ctx, username)
+	user, isPreviousUsername, err := app.usersService.GetUserByCurrentOrPreviousUsername(ctx, username)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
@@ -51,13 +83,107 @@ func (app *application) getProfile(w http.ResponseWriter, r *http.Request, ps ht
 		followerId = follower.ID
 	}
 
+	if err = app.checkProfileVisible(ctx, *user, follower, username); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	profile, err := app.profilesService.GetProfile(ctx, user.ID, &followerId)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	if err = writeJSON(w, http.StatusOK, newProfileResponse(*profile)); err != nil {
+	status := http.StatusOK
+
+	// Links to a previous username point to the canonical profile.
+	if isPreviousUsername {
+		w.Header().Set("Location", "/profiles/"+url.PathEscape(user.Username))
+		status = http.StatusMovedPermanently
+	}
+
+	if err = writeJSON(w, status, newProfileResponse(*profile)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+// checkProfileVisible hides suspended users, except from admins, and users who blocked the viewer, as if they didn't exist.
+func (app *application) checkProfileVisible(ctx context.Context, user model.Users, viewer *model.Users, username string) error {
+	if user.SuspendedAt != nil && (viewer == nil || !services.HasPermission(*viewer, services.PermissionManageUsers)) {
+		return &resourceNotFound{msg: fmt.Sprintf("Username %s not found", username)}
+	}
+
+	if viewer != nil {
+		isBlocked, err := app.profilesService.IsBlocking(ctx, user.ID, viewer.ID)
+		if err != nil {
+			return err
+		}
+
+		if *isBlocked {
+			return &resourceNotFound{msg: fmt.Sprintf("Username %s not found", username)}
+		}
+	}
+
+	return nil
+}
+
+func (app *application) listFollowers(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.listFollows(w, r, ps, app.profilesService.ListFollowers)
+}
+
+func (app *application) listFollowing(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.listFollows(w, r, ps, app.profilesService.ListFollowing)
+}
+
+func (app *application) listFollows(w http.ResponseWriter, r *http.Request, ps httprouter.Params, list func(context.Context, uuid.UUID, *uuid.UUID, services.ListFollows) (*[]services.Profile, *int, error)) {
+	ctx := r.Context()
+
+	username := ps.ByName("username")
+
+	user, err := app.usersService.GetUserByUsername(ctx, username)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	var viewerId *uuid.UUID
+	viewer := app.contextGetUser(r)
+	if viewer != nil {
+		viewerId = &viewer.ID
+	}
+
+	if err = app.checkProfileVisible(ctx, *user, viewer, username); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	canView, err := app.profilesService.CanView(ctx, viewerId, *user)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if !*canView {
+		app.writeErrorResponse(ctx, w, &forbiddenError{msg: fmt.Sprintf("User %s is private", user.ID)})
+		return
+	}
+
+	limit, offset, err := app.readPagination(r, 20)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	profiles, profilesCount, err := list(ctx, user.ID, viewerId, services.ListFollows{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newMultipleProfilesResponse(*profiles, *profilesCount)); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 	}
 }
@@ -65,6 +191,11 @@ func (app *application) getProfile(w http.ResponseWriter, r *http.Request, ps ht
 func (app *application) followUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeProfilesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	username := ps.ByName("username")
 
 	user, err := app.usersService.GetUserByUsername(ctx, username)
@@ -94,6 +225,11 @@ func (app *application) followUser(w http.ResponseWriter, r *http.Request, ps ht
 func (app *application) unfollowUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	ctx := r.Context()
 
+	if err := app.checkScope(r, services.ScopeProfilesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
 	username := ps.ByName("username")
 
 	user, err := app.usersService.GetUserByUsername(ctx, username)
@@ -119,3 +255,118 @@ func (app *application) unfollowUser(w http.ResponseWriter, r *http.Request, ps
 		app.writeErrorResponse(ctx, w, err)
 	}
 }
+
+func (app *application) blockUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.updateRelationship(w, r, ps, app.profilesService.BlockUser)
+}
+
+func (app *application) unblockUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.updateRelationship(w, r, ps, app.profilesService.UnblockUser)
+}
+
+func (app *application) muteUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.updateRelationship(w, r, ps, app.profilesService.MuteUser)
+}
+
+func (app *application) unmuteUser(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.updateRelationship(w, r, ps, app.profilesService.UnmuteUser)
+}
+
+func (app *application) updateRelationship(w http.ResponseWriter, r *http.Request, ps httprouter.Params, update func(context.Context, uuid.UUID, uuid.UUID) error) {
+	ctx := r.Context()
+
+	if err := app.checkScope(r, services.ScopeProfilesWrite); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	username := ps.ByName("username")
+
+	user, err := app.usersService.GetUserByUsername(ctx, username)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	currentUser := app.contextGetUser(r)
+
+	if err = update(ctx, currentUser.ID, user.ID); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	profile, err := app.profilesService.GetProfile(ctx, user.ID, &currentUser.ID)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newProfileResponse(*profile)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) listBlockedProfiles(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	app.listRelationships(w, r, app.profilesService.ListBlockedProfiles)
+}
+
+func (app *application) listMutedProfiles(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	app.listRelationships(w, r, app.profilesService.ListMutedProfiles)
+}
+
+func (app *application) listRelationships(w http.ResponseWriter, r *http.Request, list func(context.Context, uuid.UUID) (*[]services.Profile, error)) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	profiles, err := list(ctx, user.ID)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newMultipleProfilesResponse(*profiles, len(*profiles))); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) listFollowRequests(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	app.listRelationships(w, r, app.profilesService.ListFollowRequests)
+}
+
+func (app *application) approveFollowRequest(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.updateRelationship(w, r, ps, app.profilesService.ApproveFollowRequest)
+}
+
+func (app *application) removeFollower(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	app.updateRelationship(w, r, ps, app.profilesService.RemoveFollower)
+}
+
+func (app *application) listSuggestedProfiles(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	limit := 10
+	limitParam := r.URL.Query().Get("limit")
+	if limitParam != "" {
+		limitValue, err := strconv.Atoi(limitParam)
+		if err != nil || limitValue < 1 || limitValue > maxSuggestedProfiles {
+			app.writeErrorResponse(ctx, w, &malformedRequest{
+				msg: fmt.Sprintf("Query parameter 'limit' must be an integer between 1 and %d. Received %s", maxSuggestedProfiles, limitParam),
+			})
+			return
+		}
+		limit = limitValue
+	}
+
+	profiles, err := app.profilesService.SuggestProfiles(ctx, user.ID, limit)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newMultipleProfilesResponse(*profiles, len(*profiles))); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
@@ -11,14 +11,70 @@ func (app *application) routes() http.Handler {
 
 	router.GET("/healthz", app.healthcheck)
 
+	router.GET("/.well-known/jwks.json", app.getJSONWebKeySet)
+
 	router.GET("/user", app.authenticate(app.getCurrentUser))
 	router.POST("/users", app.registerUser)
 	router.POST("/users/login", app.login)
-	router.PUT("/user", app.authenticate(app.updateUser))
+	router.POST("/users/login/2fa", app.completeLoginChallenge)
+	router.POST("/users/token/refresh", app.refreshToken)
+	router.POST("/users/password/forgot", app.forgotPassword)
+	router.POST("/users/password/reset", app.resetPassword)
+	router.POST("/users/verify-email", app.verifyEmail)
+	router.PUT("/user", app.authenticate(app.requireSession(app.updateUser)))
+	router.DELETE("/user", app.authenticate(app.requireSession(app.deleteCurrentUser)))
+	router.GET("/user/export", app.authenticate(app.requireSession(app.exportCurrentUser)))
+	router.POST("/user/logout", app.authenticate(app.requireSession(app.logout)))
+	router.POST("/user/logout/all", app.authenticate(app.requireSession(app.logoutEverywhere)))
+	router.POST("/user/verify-email", app.authenticate(app.requireSession(app.resendEmailVerification)))
+	router.POST("/user/2fa/setup", app.authenticate(app.requireSession(app.setupTwoFactor)))
+	router.POST("/user/2fa/enable", app.authenticate(app.requireSession(app.enableTwoFactor)))
+	router.POST("/user/2fa/disable", app.authenticate(app.requireSession(app.disableTwoFactor)))
+	router.GET("/user/tokens", app.authenticate(app.requireSession(app.listPersonalAccessTokens)))
+	router.POST("/user/tokens", app.authenticate(app.requireSession(app.createPersonalAccessToken)))
+	router.DELETE("/user/tokens/:id", app.authenticate(app.requireSession(app.revokePersonalAccessToken)))
+	router.GET("/user/blocks", app.authenticate(app.listBlockedProfiles))
+	router.GET("/user/mutes", app.authenticate(app.listMutedProfiles))
+
+	router.GET("/admin/users", app.requireAdmin(app.adminListUsers))
+	router.GET("/admin/users/:id", app.requireAdmin(app.adminGetUser))
+	router.GET("/admin/users/:id/lockouts", app.requireAdmin(app.adminListAccountLockouts))
+	router.PUT("/admin/users/:id", app.requireAdmin(app.adminUpdateUser))
+	router.POST("/admin/users/:id/suspend", app.requireAdmin(app.adminSuspendUser))
+	router.POST("/admin/users/:id/password/reset", app.requireAdmin(app.adminResetPassword))
+	router.POST("/admin/users/:id/unlock", app.requireAdmin(app.adminUnlockUser))
+	router.DELETE("/admin/users/:id", app.requireAdmin(app.adminDeleteUser))
+	router.DELETE("/admin/users/:id/suspend", app.requireAdmin(app.adminUnsuspendUser))
+
+	if app.oidcService != nil {
+		router.GET("/auth/oidc/login", app.startOIDCLogin)
+		router.GET("/auth/oidc/callback", app.completeOIDCLogin)
+	}
+
+	router.GET("/profiles/:username", func() httprouter.Handle {
+		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+			username := ps.ByName("username")
 
-	router.GET("/profiles/:username", app.authenticateOptional(app.getProfile))
+			switch username {
+			case "requests":
+				app.authenticate(app.listFollowRequests)(w, r, ps)
+			case "suggestions":
+				app.authenticate(app.listSuggestedProfiles)(w, r, ps)
+			default:
+				app.authenticateOptional(app.getProfile)(w, r, ps)
+			}
+		}
+	}())
+	router.GET("/profiles/:username/followers", app.authenticateOptional(app.listFollowers))
+	router.GET("/profiles/:username/following", app.authenticateOptional(app.listFollowing))
 	router.POST("/profiles/:username/follow", app.authenticate(app.followUser))
 	router.DELETE("/profiles/:username/follow", app.authenticate(app.unfollowUser))
+	router.POST("/profiles/:username/follower", app.authenticate(app.approveFollowRequest))
+	router.DELETE("/profiles/:username/follower", app.authenticate(app.removeFollower))
+	router.POST("/profiles/:username/block", app.authenticate(app.blockUser))
+	router.DELETE("/profiles/:username/block", app.authenticate(app.unblockUser))
+	router.POST("/profiles/:username/mute", app.authenticate(app.muteUser))
+	router.DELETE("/profiles/:username/mute", app.authenticate(app.unmuteUser))
 
 	router.GET("/articles", app.authenticateOptional(app.listArticles))
 	// See https://github.com/gin-gonic/gin/issues/1301
@@ -26,18 +82,21 @@ func (app *application) routes() http.Handler {
 		return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 			slug := ps.ByName("slug")
 
-			if slug == "feed" {
+			switch slug {
+			case "feed":
 				app.authenticate(app.feedArticles)(w, r, ps)
-			} else {
+			case "search":
+				app.authenticateOptional(app.searchArticles)(w, r, ps)
+			default:
 				app.authenticateOptional(app.getArticleBySlug)(w, r, ps)
 			}
 		}
 	}())
 	router.GET("/articles/:slug/comments", app.authenticateOptional(app.getCommentsFromArticle))
-	router.POST("/articles", app.authenticate(app.createArticle))
-	router.POST("/articles/:slug/comments", app.authenticate(app.addCommentToArticle))
+	router.POST("/articles", app.authenticate(app.requireVerifiedEmail(app.createArticle)))
+	router.POST("/articles/:slug/comments", app.authenticate(app.requireVerifiedEmail(app.addCommentToArticle)))
 	router.POST("/articles/:slug/favorite", app.authenticate(app.favoriteArticle))
-	router.PUT("/articles/:slug", app.authenticate(app.updateArticle))
+	router.PUT("/articles/:slug", app.authenticate(app.requireVerifiedEmail(app.updateArticle)))
 	router.DELETE("/articles/:slug", app.authenticate(app.deleteArticle))
 	router.DELETE("/articles/:slug/favorite", app.authenticate(app.unfavoriteArticle))
 	router.DELETE("/articles/:slug/comments/:commentId", app.authenticate(app.deleteComment))
@@ -19,6 +19,17 @@ func (app *application) serve(ctx context.Context) error {
 
 	shutdownError := make(chan error)
 
+	purgeCtx, stopPurge := context.WithCancel(ctx)
+	defer stopPurge()
+
+	app.background(func() {
+		app.purgeDeletedAccounts(purgeCtx)
+	})
+
+	app.background(func() {
+		app.deleteUnusedTags(purgeCtx)
+	})
+
 	go func() {
 		quit := make(chan os.Signal, 1)
 
@@ -39,6 +50,8 @@ func (app *application) serve(ctx context.Context) error {
 
 		app.logger.InfoContext(ctx, "Completing background tasks")
 
+		stopPurge()
+
 		app.wg.Wait()
 
 		shutdownError <- nil
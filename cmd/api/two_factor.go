@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
+)
+
+type completeLoginChallengeRequest struct {
+	User completeLoginChallengeRequestUser `json:"user"`
+}
+
+type completeLoginChallengeRequestUser struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+}
+
+type enableTwoFactorRequest struct {
+	TwoFactor enableTwoFactorRequestTwoFactor `json:"twoFactor"`
+}
+
+type enableTwoFactorRequestTwoFactor struct {
+	Code string `json:"code"`
+}
+
+type disableTwoFactorRequest struct {
+	User disableTwoFactorRequestUser `json:"user"`
+}
+
+type disableTwoFactorRequestUser struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type twoFactorChallengeResponse struct {
+	TwoFactorChallenge twoFactorChallengeResponseChallenge `json:"twoFactorChallenge"`
+}
+
+type twoFactorChallengeResponseChallenge struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type twoFactorSetupResponse struct {
+	TwoFactor twoFactorSetupResponseTwoFactor `json:"twoFactor"`
+}
+
+type twoFactorSetupResponseTwoFactor struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+func newTwoFactorChallengeResponse(challenge services.TwoFactorChallenge) twoFactorChallengeResponse {
+	return twoFactorChallengeResponse{
+		TwoFactorChallenge: twoFactorChallengeResponseChallenge{
+			Token:     challenge.Token,
+			ExpiresAt: challenge.ExpiresAt,
+		},
+	}
+}
+
+func newTwoFactorSetupResponse(setup services.TwoFactorSetup) twoFactorSetupResponse {
+	return twoFactorSetupResponse{
+		TwoFactor: twoFactorSetupResponseTwoFactor{
+			Secret: setup.Secret,
+			URI:    setup.URI,
+		},
+	}
+}
+
+func (app *application) completeLoginChallenge(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request completeLoginChallengeRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user, err := app.usersService.CompleteLoginChallenge(ctx, request.User.ChallengeToken, request.User.Code, app.clientIP(r))
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	token, err := app.usersService.GetToken(ctx, user)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	userResponse := newUserResponse(*user, token.AccessToken, &token.RefreshToken)
+
+	if err = writeJSON(w, http.StatusOK, userResponse); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) setupTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	setup, err := app.usersService.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, newTwoFactorSetupResponse(*setup)); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) enableTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request enableTwoFactorRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	recoveryCodes, err := app.usersService.EnableTwoFactor(ctx, user.ID, request.TwoFactor.Code)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = writeJSON(w, http.StatusOK, recoveryCodesResponse{RecoveryCodes: *recoveryCodes}); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) disableTwoFactor(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request disableTwoFactorRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	isCorrectPassword, err := app.usersService.CheckPassword(ctx, user.ID, request.User.Password)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if !(*isCorrectPassword) {
+		app.writeErrorResponse(ctx, w, &unauthorizedError{msg: fmt.Sprintf("Incorrect password for user %s", user.ID)})
+		return
+	}
+
+	if err = app.usersService.DisableTwoFactor(ctx, user.ID, request.User.Code); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,9 +1,10 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 
+	"github.com/google/uuid"
 	"github.com/julienschmidt/httprouter"
 	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
 	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/internal/services"
@@ -28,16 +29,50 @@ type loginRequestUser struct {
 	Password string `json:"password"`
 }
 
+type refreshTokenRequest struct {
+	User refreshTokenRequestUser `json:"user"`
+}
+
+type refreshTokenRequestUser struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type forgotPasswordRequest struct {
+	User forgotPasswordRequestUser `json:"user"`
+}
+
+type forgotPasswordRequestUser struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	User resetPasswordRequestUser `json:"user"`
+}
+
+type resetPasswordRequestUser struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type verifyEmailRequest struct {
+	User verifyEmailRequestUser `json:"user"`
+}
+
+type verifyEmailRequestUser struct {
+	Token string `json:"token"`
+}
+
 type updateUserRequest struct {
 	User updateUserRequestUser `json:"user"`
 }
 
 type updateUserRequestUser struct {
-	Email    *string `json:"email"`
-	Username *string `json:"username"`
-	Password *string `json:"password"`
-	Bio      *string `json:"bio"`
-	Image    *string `json:"image"`
+	Email     *string `json:"email"`
+	Username  *string `json:"username"`
+	Password  *string `json:"password"`
+	Bio       *string `json:"bio"`
+	Image     *string `json:"image"`
+	IsPrivate *bool   `json:"isPrivate"`
 }
 
 type userResponse struct {
@@ -45,21 +80,29 @@ type userResponse struct {
 }
 
 type userResponseUser struct {
-	Email    string  `json:"email"`
-	Token    string  `json:"token"`
-	Username string  `json:"username"`
-	Bio      *string `json:"bio"`
-	Image    *string `json:"image"`
+	Email         string  `json:"email"`
+	EmailVerified bool    `json:"emailVerified"`
+	Token         string  `json:"token"`
+	RefreshToken  *string `json:"refreshToken,omitempty"`
+	Username      string  `json:"username"`
+	Bio           *string `json:"bio"`
+	Image         *string `json:"image"`
+	Role          string  `json:"role"`
+	IsPrivate     bool    `json:"isPrivate"`
 }
 
-func newUserResponse(user model.Users, token string) userResponse {
+func newUserResponse(user model.Users, token string, refreshToken *string) userResponse {
 	return userResponse{
 		User: userResponseUser{
-			Email:    user.Email,
-			Token:    token,
-			Username: user.Username,
-			Bio:      user.Bio,
-			Image:    user.Image,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt != nil,
+			Token:         token,
+			RefreshToken:  refreshToken,
+			Username:      user.Username,
+			Bio:           user.Bio,
+			Image:         user.Image,
+			Role:          user.Role,
+			IsPrivate:     user.IsPrivate,
 		},
 	}
 }
@@ -75,24 +118,35 @@ func (app *application) login(w http.ResponseWriter, r *http.Request, _ httprout
 		return
 	}
 
-	user, err := app.usersService.GetUserByEmail(ctx, request.User.Email)
+	user, err := app.usersService.Login(ctx, request.User.Email, request.User.Password, app.clientIP(r))
 	if err != nil {
-		if errNotFound, ok := err.(*services.NotFoundError); ok {
-			app.writeErrorResponse(ctx, w, &unauthorizedError{msg: errNotFound.Error()})
-		} else {
-			app.writeErrorResponse(ctx, w, err)
-		}
+		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	isCorrectPassword, err := app.usersService.CheckPassword(ctx, user.ID, request.User.Password)
+	app.writeLoginResponse(w, r, user)
+}
+
+// writeLoginResponse returns a two-factor challenge instead of the tokens when two-factor authentication is enabled.
+func (app *application) writeLoginResponse(w http.ResponseWriter, r *http.Request, user *model.Users) {
+	ctx := r.Context()
+
+	isTwoFactorEnabled, err := app.usersService.IsTwoFactorEnabled(ctx, user.ID)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	if !(*isCorrectPassword) {
-		app.writeErrorResponse(ctx, w, &unauthorizedError{msg: fmt.Sprintf("Incorrect password for user %s", user.ID)})
+	if *isTwoFactorEnabled {
+		challenge, err := app.usersService.CreateLoginChallenge(ctx, user.ID)
+		if err != nil {
+			app.writeErrorResponse(ctx, w, err)
+			return
+		}
+
+		if err = writeJSON(w, http.StatusOK, newTwoFactorChallengeResponse(*challenge)); err != nil {
+			app.writeErrorResponse(ctx, w, err)
+		}
 		return
 	}
 
@@ -102,7 +156,7 @@ func (app *application) login(w http.ResponseWriter, r *http.Request, _ httprout
 		return
 	}
 
-	userResponse := newUserResponse(*user, *token)
+	userResponse := newUserResponse(*user, token.AccessToken, &token.RefreshToken)
 
 	if err = writeJSON(w, http.StatusOK, userResponse); err != nil {
 		app.writeErrorResponse(ctx, w, err)
@@ -126,26 +180,120 @@ func (app *application) registerUser(w http.ResponseWriter, r *http.Request, _ h
 		return
 	}
 
+	app.requestEmailVerification(ctx, user.ID)
+
 	token, err := app.usersService.GetToken(ctx, user)
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	userResponse := newUserResponse(*user, *token)
+	userResponse := newUserResponse(*user, token.AccessToken, &token.RefreshToken)
 
 	if err = writeJSON(w, http.StatusCreated, userResponse); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 	}
 }
 
+func (app *application) refreshToken(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request refreshTokenRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	user, token, err := app.usersService.RefreshSession(ctx, request.User.RefreshToken)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	userResponse := newUserResponse(*user, token.AccessToken, &token.RefreshToken)
+
+	if err = writeJSON(w, http.StatusOK, userResponse); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+	}
+}
+
+func (app *application) logout(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	token := app.contextGetToken(r)
+
+	if err := app.usersService.RevokeSession(ctx, token); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) logoutEverywhere(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	if err := app.usersService.RevokeAllSessions(ctx, user.ID); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) forgotPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request forgotPasswordRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	// Sending it in the background keeps the response time from revealing whether the email is registered.
+	app.background(func() {
+		ctx := context.WithoutCancel(ctx)
+
+		if err := app.usersService.RequestPasswordReset(ctx, request.User.Email); err != nil {
+			app.logger.ErrorContext(ctx, err.Error())
+		}
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (app *application) resetPassword(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request resetPasswordRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if err = app.usersService.ResetPassword(ctx, request.User.Token, request.User.Password); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (app *application) getCurrentUser(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	ctx := r.Context()
 
 	user := app.contextGetUser(r)
 	token := app.contextGetToken(r)
 
-	userResponse := newUserResponse(*user, token)
+	userResponse := newUserResponse(*user, token, nil)
 
 	err := writeJSON(w, http.StatusOK, userResponse)
 	if err != nil {
@@ -166,17 +314,62 @@ func (app *application) updateUser(w http.ResponseWriter, r *http.Request, _ htt
 
 	user := app.contextGetUser(r)
 
+	previousEmail := user.Email
+
 	token := app.contextGetToken(r)
 
-	user, err = app.usersService.UpdateUser(ctx, user.ID, services.UpdateUser{Email: request.User.Email, Username: request.User.Username, Password: request.User.Password, Bio: request.User.Bio, Image: request.User.Image})
+	user, err = app.usersService.UpdateUser(ctx, user.ID, services.UpdateUser{Email: request.User.Email, Username: request.User.Username, Password: request.User.Password, Bio: request.User.Bio, Image: request.User.Image, IsPrivate: request.User.IsPrivate})
 	if err != nil {
 		app.writeErrorResponse(ctx, w, err)
 		return
 	}
 
-	userResponse := newUserResponse(*user, token)
+	if user.Email != previousEmail {
+		app.requestEmailVerification(ctx, user.ID)
+	}
+
+	userResponse := newUserResponse(*user, token, nil)
 
 	if err = writeJSON(w, http.StatusOK, userResponse); err != nil {
 		app.writeErrorResponse(ctx, w, err)
 	}
 }
+
+func (app *application) verifyEmail(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	var request verifyEmailRequest
+
+	err := decodeJSONBody(w, r, &request)
+	if err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	if _, err = app.usersService.VerifyEmail(ctx, request.User.Token); err != nil {
+		app.writeErrorResponse(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (app *application) resendEmailVerification(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	ctx := r.Context()
+
+	user := app.contextGetUser(r)
+
+	app.requestEmailVerification(ctx, user.ID)
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (app *application) requestEmailVerification(ctx context.Context, userId uuid.UUID) {
+	app.background(func() {
+		ctx := context.WithoutCancel(ctx)
+
+		if err := app.usersService.RequestEmailVerification(ctx, userId); err != nil {
+			app.logger.ErrorContext(ctx, err.Error())
+		}
+	})
+}
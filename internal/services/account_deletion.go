@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// RequestAccountDeletion leaves the profile and content visible, since the deletion can still be cancelled.
+func (usersService *UsersService) RequestAccountDeletion(ctx context.Context, userId uuid.UUID) (*time.Time, error) {
+	usersService.logger.InfoContext(ctx, "Requesting account deletion", "userId", userId)
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var dest struct {
+		Email               string
+		Username            string
+		DeletionRequestedAt time.Time
+	}
+
+	requestDeletionStmt := Users.UPDATE(Users.DeletionRequestedAt).SET(COALESCE(Users.DeletionRequestedAt, TimestampzT(time.Now().UTC()))).WHERE(Users.ID.EQ(UUID(userId))).RETURNING(Users.Email.AS("email"), Users.Username.AS("username"), Users.DeletionRequestedAt.AS("deletion_requested_at"))
+
+	if err = requestDeletionStmt.QueryContext(ctx, tx, &dest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
+		}
+		return nil, err
+	}
+
+	if err = usersService.revokeAllSessions(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	if err = usersService.revokeAllPersonalAccessTokens(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	deleteAt := dest.DeletionRequestedAt.Add(usersService.accountDeletionGracePeriod())
+
+	err = usersService.mailer.Send(ctx, Mail{
+		To:      dest.Email,
+		Subject: "Your account will be deleted",
+		Body: fmt.Sprintf("Hi %s,\n\nYour account and everything you created will be deleted at %s. If you change your mind, log in before then to keep your account.\n",
+			dest.Username, deleteAt.Format(time.RFC1123)),
+	})
+	if err != nil {
+		// The deletion is scheduled either way, so the email is best effort.
+		usersService.logger.ErrorContext(ctx, "Failed to send account deletion email", "userId", userId, "error", err.Error())
+	}
+
+	return &deleteAt, nil
+}
+
+func (usersService *UsersService) PurgeDeletedAccounts(ctx context.Context) (*int64, error) {
+	deleteBefore := time.Now().UTC().Add(-usersService.accountDeletionGracePeriod())
+
+	condition := Users.DeletionRequestedAt.LT_EQ(TimestampzT(deleteBefore))
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tagIds, err := listArticlesTagIds(ctx, tx, Article.AuthorID.IN(SELECT(Users.ID).FROM(Users).WHERE(condition)))
+	if err != nil {
+		return nil, err
+	}
+
+	deleteStmt := Users.DELETE().WHERE(condition)
+
+	sqlResult, err := deleteStmt.ExecContext(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = deleteUnusedTags(ctx, tx, tagIds); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if rowsAffected > 0 {
+		usersService.logger.InfoContext(ctx, "Purged deleted accounts", "count", rowsAffected)
+	}
+
+	return &rowsAffected, nil
+}
+
+func (usersService *UsersService) cancelAccountDeletion(ctx context.Context, db qrm.Executable, userId uuid.UUID) error {
+	usersService.logger.InfoContext(ctx, "Cancelling account deletion", "userId", userId)
+
+	cancelStmt := Users.UPDATE(Users.DeletionRequestedAt).SET(TimestampzExp(NULL)).WHERE(Users.ID.EQ(UUID(userId)))
+
+	_, err := cancelStmt.ExecContext(ctx, db)
+
+	return err
+}
+
+func (usersService *UsersService) accountDeletionGracePeriod() time.Duration {
+	return time.Second * time.Duration(usersService.config.AccountDeletionGracePeriodSeconds)
+}
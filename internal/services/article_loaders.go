@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+func (articlesService *ArticlesService) ListTagsByArticle(ctx context.Context, articleIds []uuid.UUID) (map[uuid.UUID][]model.ArticleTag, error) {
+	tagsByArticle := make(map[uuid.UUID][]model.ArticleTag, len(articleIds))
+
+	if len(articleIds) == 0 {
+		return tagsByArticle, nil
+	}
+
+	var dest []struct {
+		model.ArticleArticleTag
+		model.ArticleTag
+	}
+
+	listTagsStmt := SELECT(ArticleArticleTag.ID, ArticleArticleTag.ArticleID, ArticleTag.AllColumns).
+		FROM(ArticleArticleTag.INNER_JOIN(ArticleTag, ArticleTag.ID.EQ(ArticleArticleTag.ArticleTagID))).
+		WHERE(ArticleArticleTag.ArticleID.IN(uuidExpressions(articleIds)...)).
+		ORDER_BY(ArticleTag.Name)
+
+	if err := listTagsStmt.QueryContext(ctx, articlesService.db, &dest); err != nil {
+		return nil, err
+	}
+
+	for _, row := range dest {
+		tagsByArticle[*row.ArticleArticleTag.ArticleID] = append(tagsByArticle[*row.ArticleArticleTag.ArticleID], row.ArticleTag)
+	}
+
+	return tagsByArticle, nil
+}
+
+func (articlesService *ArticlesService) GetFavoritesCounts(ctx context.Context, articleIds []uuid.UUID) (map[uuid.UUID]int, error) {
+	favoritesCounts := make(map[uuid.UUID]int, len(articleIds))
+
+	if len(articleIds) == 0 {
+		return favoritesCounts, nil
+	}
+
+	var dest []struct {
+		ArticleID      uuid.UUID
+		FavoritesCount int
+	}
+
+	favoritesCountsStmt := SELECT(ArticleFavorite.ArticleID.AS("article_id"), COUNT(STAR).AS("favorites_count")).
+		FROM(ArticleFavorite).
+		WHERE(ArticleFavorite.ArticleID.IN(uuidExpressions(articleIds)...)).
+		GROUP_BY(ArticleFavorite.ArticleID)
+
+	if err := favoritesCountsStmt.QueryContext(ctx, articlesService.db, &dest); err != nil {
+		return nil, err
+	}
+
+	for _, row := range dest {
+		favoritesCounts[row.ArticleID] = row.FavoritesCount
+	}
+
+	return favoritesCounts, nil
+}
+
+func (articlesService *ArticlesService) ListFavorites(ctx context.Context, userId uuid.UUID, articleIds []uuid.UUID) (map[uuid.UUID]bool, error) {
+	favorites := make(map[uuid.UUID]bool, len(articleIds))
+
+	if len(articleIds) == 0 {
+		return favorites, nil
+	}
+
+	var dest []model.ArticleFavorite
+
+	listFavoritesStmt := SELECT(ArticleFavorite.AllColumns).
+		FROM(ArticleFavorite).
+		WHERE(ArticleFavorite.UserID.EQ(UUID(userId)).AND(ArticleFavorite.ArticleID.IN(uuidExpressions(articleIds)...)))
+
+	if err := listFavoritesStmt.QueryContext(ctx, articlesService.db, &dest); err != nil {
+		return nil, err
+	}
+
+	for _, favorite := range dest {
+		favorites[*favorite.ArticleID] = true
+	}
+
+	return favorites, nil
+}
+
+func uuidExpressions(ids []uuid.UUID) []Expression {
+	expressions := make([]Expression, len(ids))
+	for i, id := range ids {
+		expressions[i] = UUID(id)
+	}
+
+	return expressions
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestArticleLoaders(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	author := createTestUser(t, usersService)
+	reader := createTestUser(t, usersService)
+
+	tagList := []string{uniqueName(t, "tag-b-"), uniqueName(t, "tag-a-")}
+
+	taggedArticle, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, uniqueName(t, "Article "), "Description", "Body", &tagList))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	untaggedArticle, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, uniqueName(t, "Article "), "Description", "Body", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = articlesService.FavoriteArticle(ctx, reader.ID, taggedArticle.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	articleIds := []uuid.UUID{taggedArticle.ID, untaggedArticle.ID}
+
+	tagsByArticle, err := articlesService.ListTagsByArticle(ctx, articleIds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tags := tagsByArticle[taggedArticle.ID]; len(tags) != 2 || tags[0].Name != tagList[1] || tags[1].Name != tagList[0] {
+		t.Errorf("got tags %v, expected %v sorted by name", tags, tagList)
+	}
+
+	if tags := tagsByArticle[untaggedArticle.ID]; len(tags) != 0 {
+		t.Errorf("got tags %v for the untagged article, expected none", tags)
+	}
+
+	favoritesCounts, err := articlesService.GetFavoritesCounts(ctx, articleIds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if favoritesCounts[taggedArticle.ID] != 1 || favoritesCounts[untaggedArticle.ID] != 0 {
+		t.Errorf("got favorites counts %v, expected 1 and 0", favoritesCounts)
+	}
+
+	favorites, err := articlesService.ListFavorites(ctx, reader.ID, articleIds)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !favorites[taggedArticle.ID] || favorites[untaggedArticle.ID] {
+		t.Errorf("got favorites %v, expected only the tagged article", favorites)
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+const (
+	// articleSearchConfiguration must match the one article.search_vector is generated with.
+	articleSearchConfiguration   = "english"
+	articleSearchHeadlineOptions = "MaxFragments=2, MaxWords=30, MinWords=10, StartSel=<mark>, StopSel=</mark>"
+	// articleSearchHeadlineDocument escapes the body, so the highlighting is the only markup in the excerpts.
+	articleSearchHeadlineDocument = "replace(replace(replace(replace(article.body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '\"', '&quot;')"
+)
+
+type ArticleSearchResult struct {
+	Article model.Article
+	Rank    float64
+	// Headline is HTML, with the matching words between <mark> and </mark>.
+	Headline string
+}
+
+// SearchArticles rejects cursors, since results are ordered by relevance.
+func (articlesService *ArticlesService) SearchArticles(ctx context.Context, query string, listArticles ListArticles) (*[]ArticleSearchResult, *int, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, nil, &InvalidArgumentError{msg: "Search query is required"}
+	}
+
+	if listArticles.Sort != nil {
+		return nil, nil, &InvalidArgumentError{msg: "Search results are ordered by relevance and can't be sorted"}
+	}
+
+	if listArticles.Cursor != nil {
+		return nil, nil, &InvalidArgumentError{msg: "Search results can't be paginated with a cursor"}
+	}
+
+	queryArgs := RawArgs{"#query": query}
+
+	tsQuery := "websearch_to_tsquery('" + articleSearchConfiguration + "', #query)"
+
+	matches := RawBool("article.search_vector @@ "+tsQuery, queryArgs)
+	rank := RawFloat("ts_rank(article.search_vector, "+tsQuery+")", queryArgs)
+	headline := RawString("ts_headline('"+articleSearchConfiguration+"', "+articleSearchHeadlineDocument+", "+tsQuery+", '"+articleSearchHeadlineOptions+"')", queryArgs)
+
+	condition := listArticlesCondition(listArticles).AND(matches)
+
+	totalCount := IntExp(SELECT(COUNT(STAR)).FROM(Article).WHERE(condition))
+
+	searchStmt := SELECT(articleColumns, rank.AS("rank"), headline.AS("headline"), totalCount.AS("total_count")).
+		FROM(Article).
+		WHERE(condition).
+		ORDER_BY(rank.DESC(), Article.CreatedAt.DESC(), Article.ID.DESC())
+
+	if listArticles.Limit != nil {
+		searchStmt = searchStmt.LIMIT(int64(*listArticles.Limit))
+	}
+
+	if listArticles.Offset != nil {
+		searchStmt = searchStmt.OFFSET(int64(*listArticles.Offset))
+	}
+
+	var dest []struct {
+		model.Article
+		Rank       float64
+		Headline   string
+		TotalCount int
+	}
+
+	if err := searchStmt.QueryContext(ctx, articlesService.db, &dest); err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]ArticleSearchResult, len(dest))
+	articlesCount := 0
+
+	for i, row := range dest {
+		results[i] = ArticleSearchResult{
+			Article:  row.Article,
+			Rank:     row.Rank,
+			Headline: row.Headline,
+		}
+		articlesCount = row.TotalCount
+	}
+
+	// An empty page, past the end or with a limit of 0, has no rows to carry the total.
+	if len(dest) == 0 && ((listArticles.Offset != nil && *listArticles.Offset > 0) || (listArticles.Limit != nil && *listArticles.Limit == 0)) {
+		totalCount, err := articlesService.countArticles(ctx, Article, condition)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		articlesCount = *totalCount
+	}
+
+	return &results, &articlesCount, nil
+}
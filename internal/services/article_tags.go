@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// getOrCreateTag locks the tag until the transaction ends, so deleteUnusedTags skips it before it's linked.
+func (articlesService *ArticlesService) getOrCreateTag(ctx context.Context, db qrm.DB, tagName string) (*model.ArticleTag, error) {
+	for {
+		insertTagStmt := ArticleTag.INSERT(ArticleTag.Name).VALUES(String(tagName)).ON_CONFLICT(ArticleTag.Name).DO_NOTHING()
+
+		sqlResult, err := insertTagStmt.ExecContext(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+
+		rowsAffected, err := sqlResult.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+
+		if rowsAffected > 0 {
+			articlesService.logger.InfoContext(ctx, "Created article tag", "name", tagName)
+		}
+
+		var tag model.ArticleTag
+
+		getTagStmt := ArticleTag.SELECT(ArticleTag.AllColumns).WHERE(ArticleTag.Name.EQ(String(tagName))).FOR(KEY_SHARE())
+
+		err = getTagStmt.QueryContext(ctx, db, &tag)
+		if err == nil {
+			return &tag, nil
+		}
+
+		// A concurrent deleteUnusedTags deleted the tag before it could be locked.
+		if !errors.Is(err, qrm.ErrNoRows) {
+			return nil, err
+		}
+	}
+}
+
+func (articlesService *ArticlesService) addArticleTags(ctx context.Context, db qrm.DB, articleId uuid.UUID, tagNames []string) error {
+	currentTags, err := listArticleTags(ctx, db, articleId)
+	if err != nil {
+		return err
+	}
+
+	tagged := map[string]bool{}
+	for _, tag := range currentTags {
+		tagged[tag.Name] = true
+	}
+
+	for _, tagName := range tagNames {
+		tagName = articlesService.makeTagName(tagName)
+
+		if tagName == "" || tagged[tagName] {
+			continue
+		}
+
+		tag, err := articlesService.getOrCreateTag(ctx, db, tagName)
+		if err != nil {
+			return err
+		}
+
+		articleArticleTag := model.ArticleArticleTag{
+			ArticleID:    &articleId,
+			ArticleTagID: &tag.ID,
+		}
+
+		insertArticleArticleTagStmt := ArticleArticleTag.INSERT(ArticleArticleTag.ArticleID, ArticleArticleTag.ArticleTagID).MODEL(articleArticleTag)
+
+		if _, err = insertArticleArticleTagStmt.ExecContext(ctx, db); err != nil {
+			return err
+		}
+
+		tagged[tagName] = true
+	}
+
+	return nil
+}
+
+func (articlesService *ArticlesService) removeArticleTags(ctx context.Context, db qrm.DB, articleId uuid.UUID, tagNames []string) error {
+	var sqlTagNames []Expression
+
+	for _, tagName := range tagNames {
+		sqlTagNames = append(sqlTagNames, String(articlesService.makeTagName(tagName)))
+	}
+
+	if len(sqlTagNames) == 0 {
+		return nil
+	}
+
+	var removed []model.ArticleArticleTag
+
+	removeStmt := ArticleArticleTag.DELETE().
+		WHERE(ArticleArticleTag.ArticleID.EQ(UUID(articleId)).AND(ArticleArticleTag.ArticleTagID.IN(SELECT(ArticleTag.ID).FROM(ArticleTag).WHERE(ArticleTag.Name.IN(sqlTagNames...))))).
+		RETURNING(ArticleArticleTag.AllColumns)
+
+	if err := removeStmt.QueryContext(ctx, db, &removed); err != nil {
+		return err
+	}
+
+	removedTagIds := make([]uuid.UUID, len(removed))
+	for i, articleArticleTag := range removed {
+		removedTagIds[i] = *articleArticleTag.ArticleTagID
+	}
+
+	return deleteUnusedTags(ctx, db, removedTagIds)
+}
+
+func (articlesService *ArticlesService) setArticleTags(ctx context.Context, db qrm.DB, articleId uuid.UUID, tagNames []string) error {
+	currentTags, err := listArticleTags(ctx, db, articleId)
+	if err != nil {
+		return err
+	}
+
+	keep := map[string]bool{}
+	for _, tagName := range tagNames {
+		keep[articlesService.makeTagName(tagName)] = true
+	}
+
+	var removedTagNames []string
+	for _, tag := range currentTags {
+		if !keep[tag.Name] {
+			removedTagNames = append(removedTagNames, tag.Name)
+		}
+	}
+
+	if err = articlesService.removeArticleTags(ctx, db, articleId, removedTagNames); err != nil {
+		return err
+	}
+
+	return articlesService.addArticleTags(ctx, db, articleId, tagNames)
+}
+
+func listArticleTags(ctx context.Context, db qrm.Queryable, articleId uuid.UUID) ([]model.ArticleTag, error) {
+	var tags []model.ArticleTag
+
+	listTagsStmt := SELECT(ArticleTag.AllColumns).
+		FROM(ArticleArticleTag.INNER_JOIN(ArticleTag, ArticleTag.ID.EQ(ArticleArticleTag.ArticleTagID))).
+		WHERE(ArticleArticleTag.ArticleID.EQ(UUID(articleId)))
+
+	if err := listTagsStmt.QueryContext(ctx, db, &tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func listArticlesTagIds(ctx context.Context, db qrm.Queryable, articleCondition BoolExpression) ([]uuid.UUID, error) {
+	var dest []struct {
+		ArticleTagID uuid.UUID
+	}
+
+	listTagIdsStmt := SELECT(ArticleArticleTag.ArticleTagID.AS("article_tag_id")).
+		DISTINCT().
+		FROM(ArticleArticleTag.INNER_JOIN(Article, Article.ID.EQ(ArticleArticleTag.ArticleID))).
+		WHERE(articleCondition)
+
+	if err := listTagIdsStmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	tagIds := make([]uuid.UUID, len(dest))
+	for i, row := range dest {
+		tagIds[i] = row.ArticleTagID
+	}
+
+	return tagIds, nil
+}
+
+// DeleteUnusedTags cleans up the tags deleteUnusedTags skipped while they were locked by transactions that rolled back.
+func (articlesService *ArticlesService) DeleteUnusedTags(ctx context.Context) error {
+	tx, err := articlesService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var unusedTags []model.ArticleTag
+
+	listUnusedTagsStmt := ArticleTag.SELECT(ArticleTag.AllColumns).
+		WHERE(NOT(EXISTS(ArticleArticleTag.SELECT(ArticleArticleTag.ID).WHERE(ArticleArticleTag.ArticleTagID.EQ(ArticleTag.ID)))))
+
+	if err = listUnusedTagsStmt.QueryContext(ctx, tx, &unusedTags); err != nil {
+		return err
+	}
+
+	if len(unusedTags) == 0 {
+		return nil
+	}
+
+	articlesService.logger.InfoContext(ctx, "Deleting unused article tags", "count", len(unusedTags))
+
+	unusedTagIds := make([]uuid.UUID, len(unusedTags))
+	for i, tag := range unusedTags {
+		unusedTagIds[i] = tag.ID
+	}
+
+	if err = deleteUnusedTags(ctx, tx, unusedTagIds); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// deleteUnusedTags checks the tags for articles only once they're locked, to see the transactions that committed meanwhile.
+func deleteUnusedTags(ctx context.Context, db qrm.DB, tagIds []uuid.UUID) error {
+	if len(tagIds) == 0 {
+		return nil
+	}
+
+	var lockedTags []model.ArticleTag
+
+	lockTagsStmt := ArticleTag.SELECT(ArticleTag.AllColumns).WHERE(ArticleTag.ID.IN(uuidExpressions(tagIds)...)).FOR(UPDATE().SKIP_LOCKED())
+
+	if err := lockTagsStmt.QueryContext(ctx, db, &lockedTags); err != nil {
+		return err
+	}
+
+	if len(lockedTags) == 0 {
+		return nil
+	}
+
+	lockedTagIds := make([]uuid.UUID, len(lockedTags))
+	for i, tag := range lockedTags {
+		lockedTagIds[i] = tag.ID
+	}
+
+	deleteTagsStmt := ArticleTag.DELETE().
+		WHERE(ArticleTag.ID.IN(uuidExpressions(lockedTagIds)...).
+			AND(NOT(EXISTS(ArticleArticleTag.SELECT(ArticleArticleTag.ID).WHERE(ArticleArticleTag.ArticleTagID.EQ(ArticleTag.ID))))))
+
+	_, err := deleteTagsStmt.ExecContext(ctx, db)
+
+	return err
+}
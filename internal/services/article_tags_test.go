@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+func TestDeleteUnusedTagsSkipsTagsBeingAdded(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	// Both transactions run in this goroutine, so waiting for a lock would block the test until it times out.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	author := createTestUser(t, usersService)
+
+	article, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, "Tagging", "Tagging articles", "Tagging articles.", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagName := uniqueName(t, "tag")
+
+	var unusedTag model.ArticleTag
+
+	insertTagStmt := ArticleTag.INSERT(ArticleTag.Name).VALUES(String(tagName)).RETURNING(ArticleTag.AllColumns)
+
+	if err = insertTagStmt.QueryContext(ctx, db, &unusedTag); err != nil {
+		t.Fatal(err)
+	}
+
+	taggingTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taggingTx.Rollback()
+
+	tag, err := articlesService.getOrCreateTag(ctx, taggingTx, tagName)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tag.ID != unusedTag.ID {
+		t.Fatalf("got tag %s, expected the existing tag %s", tag.ID, unusedTag.ID)
+	}
+
+	deletingTx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer deletingTx.Rollback()
+
+	if err = deleteUnusedTags(ctx, deletingTx, []uuid.UUID{tag.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = deletingTx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = articlesService.addArticleTags(ctx, taggingTx, article.ID, []string{tagName}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = taggingTx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := listArticleTags(ctx, db, article.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tags) != 1 || tags[0].ID != unusedTag.ID {
+		t.Errorf("got tags %v, expected tag %s", tags, unusedTag.ID)
+	}
+}
+
+func TestUpdateArticleTags(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	author := createTestUser(t, usersService)
+
+	keptTag := uniqueName(t, "tag-a-")
+	removedTag := uniqueName(t, "tag-b-")
+	addedTag := uniqueName(t, "tag-c-")
+
+	tagList := []string{keptTag, removedTag}
+
+	article, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, uniqueName(t, "Article "), "Description", "Body", &tagList))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = articlesService.UpdateArticle(ctx, article.ID, UpdateArticle{AddTags: &[]string{addedTag}, RemoveTags: &[]string{removedTag}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, err := articlesService.ListTags(ctx, ListTags{ArticleID: &article.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tagNames []string
+	for _, tag := range *tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+
+	if len(tagNames) != 2 || !slices.Contains(tagNames, keptTag) || !slices.Contains(tagNames, addedTag) {
+		t.Errorf("got tags %v, expected %s and %s", tagNames, keptTag, addedTag)
+	}
+
+	var removedTags []model.ArticleTag
+
+	getRemovedTagStmt := ArticleTag.SELECT(ArticleTag.AllColumns).WHERE(ArticleTag.Name.EQ(String(removedTag)))
+
+	if err = getRemovedTagStmt.QueryContext(ctx, db, &removedTags); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removedTags) != 0 {
+		t.Errorf("expected tag %s to be deleted once no article has it", removedTag)
+	}
+
+	if _, err = articlesService.UpdateArticle(ctx, article.ID, UpdateArticle{TagList: &tagList, AddTags: &[]string{addedTag}}); err == nil {
+		t.Error("expected an error combining tagList with addTags")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	. "github.com/go-jet/jet/v2/postgres"
@@ -16,6 +17,9 @@ import (
 	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
 )
 
+// articleColumns are the columns of article loaded with articles. search_vector is only used to search them.
+var articleColumns = Article.AllColumns.Except(Article.SearchVector)
+
 type ArticlesService struct {
 	db           *sql.DB
 	logger       *slog.Logger
@@ -48,18 +52,54 @@ func NewCreateArticle(authorId uuid.UUID, title string, description string, body
 	}
 }
 
+const (
+	ArticleSortNewest          = "newest"
+	ArticleSortOldest          = "oldest"
+	ArticleSortMostFavorited   = "mostFavorited"
+	ArticleSortRecentlyUpdated = "recentlyUpdated"
+	ArticleSortMostCommented   = "mostCommented"
+)
+
+var ArticleSorts = []string{ArticleSortNewest, ArticleSortOldest, ArticleSortMostFavorited, ArticleSortRecentlyUpdated, ArticleSortMostCommented}
+
 type ListArticles struct {
 	AuthorIDs         *[]uuid.UUID
 	FavoritedByUserID *uuid.UUID
-	TagName           *string
-	Limit             *int
-	Offset            *int
+	// HidePrivate leaves out the articles of private users, except for ViewerID and their followers.
+	HidePrivate bool
+	// MutedByUserID leaves out the articles of the users this user muted.
+	MutedByUserID *uuid.UUID
+	// TagNames keeps the articles with any of the tags, or all of them if MatchAllTags.
+	TagNames      *[]string
+	MatchAllTags  bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	ViewerID      *uuid.UUID
+	// Sort is one of ArticleSorts, ArticleSortNewest by default.
+	Sort *string
+	// Cursor starts the page after the article it points to, instead of at Offset.
+	Cursor *Cursor
+	Limit  *int
+	Offset *int
+}
+
+type Feed struct {
+	// Cursor starts the page after the article it points to, instead of at Offset.
+	Cursor *Cursor
+	Limit  *int
+	Offset *int
 }
 
 type UpdateArticle struct {
 	Title       *string
 	Description *string
 	Body        *string
+	// TagList replaces the tags of the article. It can't be combined with AddTags and RemoveTags.
+	TagList    *[]string
+	AddTags    *[]string
+	RemoveTags *[]string
 }
 
 type ListTags struct {
@@ -68,6 +108,12 @@ type ListTags struct {
 
 type ListComments struct {
 	ArticleID *uuid.UUID
+	AuthorID  *uuid.UUID
+	// MutedByUserID leaves out the comments of the users this user muted.
+	MutedByUserID *uuid.UUID
+	// Cursor starts the page after the comment it points to.
+	Cursor *Cursor
+	Limit  *int
 }
 
 func (articlesService *ArticlesService) CreateArticle(ctx context.Context, createArticle CreateArticle) (*model.Article, error) {
@@ -97,47 +143,15 @@ func (articlesService *ArticlesService) CreateArticle(ctx context.Context, creat
 	}
 	defer tx.Rollback()
 
-	articleInsertStmt := Article.INSERT(Article.AuthorID, Article.Slug, Article.Title, Article.Description, Article.Body).MODEL(article).RETURNING(Article.AllColumns)
+	articleInsertStmt := Article.INSERT(Article.AuthorID, Article.Slug, Article.Title, Article.Description, Article.Body).MODEL(article).RETURNING(articleColumns)
 
 	if err = articleInsertStmt.QueryContext(ctx, tx, &article); err != nil {
 		return nil, err
 	}
 
 	if createArticle.TagList != nil {
-		for _, tagName := range *createArticle.TagList {
-			tagName = articlesService.makeTagName(tagName)
-
-			tag, err := articlesService.getTagByName(ctx, tagName)
-			if err != nil {
-				if _, ok := err.(*NotFoundError); ok {
-					tagModel := model.ArticleTag{
-						Name: tagName,
-					}
-
-					insertTagStmt := ArticleTag.INSERT(ArticleTag.Name).MODEL(tagModel).RETURNING(ArticleTag.AllColumns)
-
-					articlesService.logger.InfoContext(ctx, "Creating article tag", "name", tagModel.Name)
-
-					if err = insertTagStmt.QueryContext(ctx, tx, &tagModel); err != nil {
-						return nil, err
-					}
-
-					tag = &tagModel
-				} else {
-					return nil, err
-				}
-			}
-
-			articleArticleTag := model.ArticleArticleTag{
-				ArticleID:    &article.ID,
-				ArticleTagID: &tag.ID,
-			}
-
-			insertArticleArticleTagStmt := ArticleArticleTag.INSERT(ArticleArticleTag.ArticleID, ArticleArticleTag.ArticleTagID).MODEL(articleArticleTag)
-
-			if _, err = insertArticleArticleTagStmt.ExecContext(ctx, tx); err != nil {
-				return nil, err
-			}
+		if err = articlesService.addArticleTags(ctx, tx, article.ID, *createArticle.TagList); err != nil {
+			return nil, err
 		}
 	}
 
@@ -153,7 +167,7 @@ func (articlesService *ArticlesService) CreateArticle(ctx context.Context, creat
 func (articlesService *ArticlesService) GetArticleById(ctx context.Context, articleId uuid.UUID) (*model.Article, error) {
 	var article model.Article
 
-	getArticleByIdStmt := Article.SELECT(Article.AllColumns).WHERE(Article.ID.EQ(UUID(articleId)))
+	getArticleByIdStmt := Article.SELECT(articleColumns).WHERE(Article.ID.EQ(UUID(articleId)))
 
 	err := getArticleByIdStmt.QueryContext(ctx, articlesService.db, &article)
 	if err != nil {
@@ -166,10 +180,32 @@ func (articlesService *ArticlesService) GetArticleById(ctx context.Context, arti
 	return &article, nil
 }
 
+func (articlesService *ArticlesService) GetArticleSlugs(ctx context.Context, articleIds []uuid.UUID) (map[uuid.UUID]string, error) {
+	articleSlugs := make(map[uuid.UUID]string, len(articleIds))
+
+	if len(articleIds) == 0 {
+		return articleSlugs, nil
+	}
+
+	var articles []model.Article
+
+	getArticleSlugsStmt := SELECT(Article.ID, Article.Slug).FROM(Article).WHERE(Article.ID.IN(uuidExpressions(articleIds)...))
+
+	if err := getArticleSlugsStmt.QueryContext(ctx, articlesService.db, &articles); err != nil {
+		return nil, err
+	}
+
+	for _, article := range articles {
+		articleSlugs[article.ID] = article.Slug
+	}
+
+	return articleSlugs, nil
+}
+
 func (articlesService *ArticlesService) GetArticleBySlug(ctx context.Context, slug string) (*model.Article, error) {
 	var article model.Article
 
-	getArticleBySlugStmt := Article.SELECT(Article.AllColumns).WHERE(Article.Slug.EQ(String(slug)))
+	getArticleBySlugStmt := Article.SELECT(articleColumns).WHERE(Article.Slug.EQ(String(slug)))
 
 	err := getArticleBySlugStmt.QueryContext(ctx, articlesService.db, &article)
 	if err != nil {
@@ -182,8 +218,27 @@ func (articlesService *ArticlesService) GetArticleBySlug(ctx context.Context, sl
 	return &article, nil
 }
 
-func (articlesService *ArticlesService) ListArticles(ctx context.Context, listArticles ListArticles) (*[]model.Article, error) {
-	condition := Bool(true)
+func (articlesService *ArticlesService) ListArticles(ctx context.Context, listArticles ListArticles) (*[]model.Article, *int, error) {
+	sort := ArticleSortNewest
+	if listArticles.Sort != nil {
+		sort = *listArticles.Sort
+	}
+
+	if _, ok := articleSortOrderBy[sort]; !ok {
+		return nil, nil, &InvalidArgumentError{msg: fmt.Sprintf("Invalid sort %s. Must be one of %s", sort, strings.Join(ArticleSorts, ", "))}
+	}
+
+	if listArticles.Cursor != nil && sort != ArticleSortNewest {
+		return nil, nil, &InvalidArgumentError{msg: fmt.Sprintf("Articles sorted by %s can't be paginated with a cursor", sort)}
+	}
+
+	condition := listArticlesCondition(listArticles)
+
+	return articlesService.listArticles(ctx, Article, condition, sort, listArticles.Cursor, listArticles.Limit, listArticles.Offset)
+}
+
+func listArticlesCondition(listArticles ListArticles) BoolExpression {
+	condition := isNotSuspended(Article.AuthorID)
 
 	if listArticles.AuthorIDs != nil {
 		if len(*listArticles.AuthorIDs) > 0 {
@@ -203,35 +258,160 @@ func (articlesService *ArticlesService) ListArticles(ctx context.Context, listAr
 		condition = condition.AND(Article.ID.IN(SELECT(ArticleFavorite.ArticleID).FROM(ArticleFavorite).WHERE(ArticleFavorite.UserID.EQ(UUID(*listArticles.FavoritedByUserID)))))
 	}
 
-	if listArticles.TagName != nil {
-		condition = condition.AND(
-			Article.ID.IN(
-				SELECT(ArticleArticleTag.ArticleID).FROM(
-					ArticleArticleTag.INNER_JOIN(ArticleTag, ArticleArticleTag.ArticleTagID.EQ(ArticleTag.ID))).WHERE(ArticleTag.Name.EQ(String(*listArticles.TagName)))))
+	if listArticles.HidePrivate {
+		condition = condition.AND(isVisibleTo(Article.AuthorID, listArticles.ViewerID))
 	}
 
-	listArticlesStmt := SELECT(Article.AllColumns).FROM(Article).WHERE(condition).ORDER_BY(Article.CreatedAt.DESC())
+	if listArticles.MutedByUserID != nil {
+		condition = condition.AND(NOT(isMutedBy(Article.AuthorID, *listArticles.MutedByUserID)))
+	}
+
+	if listArticles.TagNames != nil {
+		if listArticles.MatchAllTags {
+			for _, tagName := range *listArticles.TagNames {
+				condition = condition.AND(hasTag(ArticleTag.Name.EQ(String(tagName))))
+			}
+		} else if len(*listArticles.TagNames) > 0 {
+			var sqlTagNames []Expression
+
+			for _, tagName := range *listArticles.TagNames {
+				sqlTagNames = append(sqlTagNames, String(tagName))
+			}
 
-	if listArticles.Limit != nil {
-		listArticlesStmt = listArticlesStmt.LIMIT(int64(*listArticles.Limit))
+			condition = condition.AND(hasTag(ArticleTag.Name.IN(sqlTagNames...)))
+		} else {
+			condition = condition.AND(Bool(false))
+		}
 	}
 
-	if listArticles.Offset != nil {
-		listArticlesStmt = listArticlesStmt.OFFSET(int64(*listArticles.Offset))
+	if listArticles.CreatedAfter != nil {
+		condition = condition.AND(Article.CreatedAt.GT_EQ(TimestampzT(*listArticles.CreatedAfter)))
 	}
 
-	var articles []model.Article
+	if listArticles.CreatedBefore != nil {
+		condition = condition.AND(Article.CreatedAt.LT(TimestampzT(*listArticles.CreatedBefore)))
+	}
+
+	if listArticles.UpdatedAfter != nil {
+		condition = condition.AND(Article.UpdatedAt.GT_EQ(TimestampzT(*listArticles.UpdatedAfter)))
+	}
+
+	if listArticles.UpdatedBefore != nil {
+		condition = condition.AND(Article.UpdatedAt.LT(TimestampzT(*listArticles.UpdatedBefore)))
+	}
+
+	return condition
+}
+
+func (articlesService *ArticlesService) Feed(ctx context.Context, userId uuid.UUID, feed Feed) (*[]model.Article, *int, error) {
+	from := Article.INNER_JOIN(Follow, Follow.FollowedID.EQ(Article.AuthorID))
 
-	err := listArticlesStmt.QueryContext(ctx, articlesService.db, &articles)
+	condition := Follow.FollowerID.EQ(UUID(userId)).
+		AND(NOT(isMutedBy(Article.AuthorID, userId))).
+		AND(isNotSuspended(Article.AuthorID))
+
+	return articlesService.listArticles(ctx, from, condition, ArticleSortNewest, feed.Cursor, feed.Limit, feed.Offset)
+}
+
+func (articlesService *ArticlesService) listArticles(ctx context.Context, from ReadableTable, condition BoolExpression, sort string, cursor *Cursor, limit *int, offset *int) (*[]model.Article, *int, error) {
+	// The total is counted over all the matching articles, regardless of the page, in the same query as the page.
+	totalCount := IntExp(SELECT(COUNT(STAR)).FROM(from).WHERE(condition))
+
+	pageCondition := condition
+
+	if cursor != nil {
+		pageCondition = pageCondition.AND(cursor.after(Article.CreatedAt, Article.ID))
+	}
+
+	projections := ProjectionList{articleColumns, totalCount.AS("total_count")}
+
+	if count, ok := articleSortCounts[sort]; ok {
+		projections = append(projections, count.AS(sortCount.Name()))
+	}
+
+	listArticlesStmt := SELECT(projections).FROM(from).WHERE(pageCondition).ORDER_BY(articleSortOrderBy[sort]...)
+
+	if limit != nil {
+		listArticlesStmt = listArticlesStmt.LIMIT(int64(*limit))
+	}
+
+	if offset != nil {
+		listArticlesStmt = listArticlesStmt.OFFSET(int64(*offset))
+	}
+
+	var dest []struct {
+		model.Article
+		TotalCount int
+	}
+
+	err := listArticlesStmt.QueryContext(ctx, articlesService.db, &dest)
 	if err != nil {
+		return nil, nil, err
+	}
+
+	articles := make([]model.Article, len(dest))
+	articlesCount := 0
+
+	for i, row := range dest {
+		articles[i] = row.Article
+		articlesCount = row.TotalCount
+	}
+
+	// An empty page, past the end or with a limit of 0, has no rows to carry the total.
+	if len(dest) == 0 && (cursor != nil || (offset != nil && *offset > 0) || (limit != nil && *limit == 0)) {
+		totalCount, err := articlesService.countArticles(ctx, from, condition)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		articlesCount = *totalCount
+	}
+
+	return &articles, &articlesCount, nil
+}
+
+// sortCount is selected to be ordered by, since jet only wraps subqueries in parentheses in projections.
+var sortCount = IntegerColumn("sort_count")
+
+var articleSortOrderBy = map[string][]OrderByClause{
+	ArticleSortNewest:          {Article.CreatedAt.DESC(), Article.ID.DESC()},
+	ArticleSortOldest:          {Article.CreatedAt.ASC(), Article.ID.ASC()},
+	ArticleSortMostFavorited:   {sortCount.DESC(), Article.CreatedAt.DESC(), Article.ID.DESC()},
+	ArticleSortRecentlyUpdated: {Article.UpdatedAt.DESC(), Article.ID.DESC()},
+	ArticleSortMostCommented:   {sortCount.DESC(), Article.CreatedAt.DESC(), Article.ID.DESC()},
+}
+
+var articleSortCounts = map[string]IntegerExpression{
+	ArticleSortMostFavorited: IntExp(SELECT(COUNT(STAR)).FROM(ArticleFavorite).WHERE(ArticleFavorite.ArticleID.EQ(Article.ID))),
+	ArticleSortMostCommented: IntExp(SELECT(COUNT(STAR)).FROM(ArticleComment).WHERE(ArticleComment.ArticleID.EQ(Article.ID))),
+}
+
+func hasTag(tagCondition BoolExpression) BoolExpression {
+	return Article.ID.IN(
+		SELECT(ArticleArticleTag.ArticleID).FROM(
+			ArticleArticleTag.INNER_JOIN(ArticleTag, ArticleArticleTag.ArticleTagID.EQ(ArticleTag.ID))).WHERE(tagCondition))
+}
+
+func (articlesService *ArticlesService) countArticles(ctx context.Context, from ReadableTable, condition BoolExpression) (*int, error) {
+	var dest struct {
+		TotalCount int
+	}
+
+	countStmt := SELECT(COUNT(STAR).AS("total_count")).FROM(from).WHERE(condition)
+
+	if err := countStmt.QueryContext(ctx, articlesService.db, &dest); err != nil {
 		return nil, err
 	}
 
-	return &articles, nil
+	return &dest.TotalCount, nil
 }
 
 func (articlesService *ArticlesService) UpdateArticle(ctx context.Context, articleId uuid.UUID, updateArticle UpdateArticle) (*model.Article, error) {
-	articlesService.logger.InfoContext(ctx, "Updating article", "articleId", articleId, "title", updateArticle.Title, "description", updateArticle.Description, "body", updateArticle.Body)
+	articlesService.logger.InfoContext(ctx, "Updating article", "articleId", articleId, "title", updateArticle.Title, "description", updateArticle.Description, "body", updateArticle.Body, "tagList", updateArticle.TagList, "addTags", updateArticle.AddTags, "removeTags", updateArticle.RemoveTags)
+
+	if updateArticle.TagList != nil && (updateArticle.AddTags != nil || updateArticle.RemoveTags != nil) {
+		return nil, &InvalidArgumentError{msg: "tagList can't be combined with addTags and removeTags"}
+	}
 
 	article, err := articlesService.GetArticleById(ctx, articleId)
 	if err != nil {
@@ -265,22 +445,61 @@ func (articlesService *ArticlesService) UpdateArticle(ctx context.Context, artic
 
 	article.UpdatedAt = &now
 
+	tx, err := articlesService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	updateArticleStmt := Article.UPDATE(Article.Slug, Article.Title, Article.Description, Article.Body, Article.UpdatedAt).MODEL(article).WHERE(Article.ID.EQ(UUID(article.ID)))
 
-	_, err = updateArticleStmt.ExecContext(ctx, articlesService.db)
+	_, err = updateArticleStmt.ExecContext(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
 
+	if updateArticle.TagList != nil {
+		if err = articlesService.setArticleTags(ctx, tx, article.ID, *updateArticle.TagList); err != nil {
+			return nil, err
+		}
+	}
+
+	if updateArticle.RemoveTags != nil {
+		if err = articlesService.removeArticleTags(ctx, tx, article.ID, *updateArticle.RemoveTags); err != nil {
+			return nil, err
+		}
+	}
+
+	if updateArticle.AddTags != nil {
+		if err = articlesService.addArticleTags(ctx, tx, article.ID, *updateArticle.AddTags); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
 	return article, nil
 }
 
 func (articlesService *ArticlesService) DeleteArticle(ctx context.Context, articleId uuid.UUID) error {
 	articlesService.logger.InfoContext(ctx, "Deleting article", "articleId", articleId)
 
+	tx, err := articlesService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tagIds, err := listArticlesTagIds(ctx, tx, Article.ID.EQ(UUID(articleId)))
+	if err != nil {
+		return err
+	}
+
 	deleteArticleStmt := Article.DELETE().WHERE(Article.ID.EQ(UUID(articleId)))
 
-	sqlResult, err := deleteArticleStmt.ExecContext(ctx, articlesService.db)
+	sqlResult, err := deleteArticleStmt.ExecContext(ctx, tx)
 	if err != nil {
 		return err
 	}
@@ -294,7 +513,11 @@ func (articlesService *ArticlesService) DeleteArticle(ctx context.Context, artic
 		return &NotFoundError{msg: fmt.Sprintf("Article %s not found", articleId.String())}
 	}
 
-	return nil
+	if err = deleteUnusedTags(ctx, tx, tagIds); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (articlesService *ArticlesService) CreateComment(ctx context.Context, articleId uuid.UUID, authorId uuid.UUID, body string) (*model.ArticleComment, error) {
@@ -310,6 +533,10 @@ func (articlesService *ArticlesService) CreateComment(ctx context.Context, artic
 		return nil, err
 	}
 
+	if err = checkNotBlocked(ctx, articlesService.db, *article.AuthorID, author.ID); err != nil {
+		return nil, err
+	}
+
 	comment := model.ArticleComment{
 		ArticleID: &article.ID,
 		AuthorID:  &author.ID,
@@ -347,9 +574,25 @@ func (articlesService *ArticlesService) ListComments(ctx context.Context, listCo
 		condition = condition.AND(ArticleComment.ArticleID.EQ(UUID(listComments.ArticleID)))
 	}
 
+	if listComments.AuthorID != nil {
+		condition = condition.AND(ArticleComment.AuthorID.EQ(UUID(listComments.AuthorID)))
+	}
+
+	if listComments.MutedByUserID != nil {
+		condition = condition.AND(NOT(isMutedBy(ArticleComment.AuthorID, *listComments.MutedByUserID)))
+	}
+
+	if listComments.Cursor != nil {
+		condition = condition.AND(listComments.Cursor.after(ArticleComment.CreatedAt, ArticleComment.ID))
+	}
+
 	var comments []model.ArticleComment
 
-	listCommentsStmt := SELECT(ArticleComment.AllColumns).FROM(ArticleComment).WHERE(condition).ORDER_BY(ArticleComment.CreatedAt.DESC())
+	listCommentsStmt := SELECT(ArticleComment.AllColumns).FROM(ArticleComment).WHERE(condition).ORDER_BY(ArticleComment.CreatedAt.DESC(), ArticleComment.ID.DESC())
+
+	if listComments.Limit != nil {
+		listCommentsStmt = listCommentsStmt.LIMIT(int64(*listComments.Limit))
+	}
 
 	err := listCommentsStmt.QueryContext(ctx, articlesService.db, &comments)
 	if err != nil {
@@ -455,19 +698,20 @@ func (articlesService *ArticlesService) IsFavorite(ctx context.Context, userId u
 	return &isFavoriteDest.IsFavorite, nil
 }
 
-func (articlesService *ArticlesService) GetFavoritesCount(ctx context.Context, articleId uuid.UUID) (*int, error) {
-	var favoritesCountDest struct {
-		FavoritesCount int
+// IsArticleVisible is false for the articles of suspended users, and of private users the viewer doesn't follow.
+func (articlesService *ArticlesService) IsArticleVisible(ctx context.Context, articleId uuid.UUID, viewerId *uuid.UUID) (*bool, error) {
+	var isVisibleDest struct {
+		IsVisible bool
 	}
 
-	isFavoriteStmt := SELECT(COUNT(STAR).AS("favorites_count")).FROM(ArticleFavorite).WHERE(ArticleFavorite.ArticleID.EQ(UUID(articleId)))
+	isVisibleStmt := SELECT(EXISTS(Article.SELECT(Article.ID).WHERE(Article.ID.EQ(UUID(articleId)).AND(isVisibleTo(Article.AuthorID, viewerId)).AND(isNotSuspended(Article.AuthorID)))).AS("is_visible"))
 
-	err := isFavoriteStmt.QueryContext(ctx, articlesService.db, &favoritesCountDest)
+	err := isVisibleStmt.QueryContext(ctx, articlesService.db, &isVisibleDest)
 	if err != nil {
 		return nil, err
 	}
 
-	return &favoritesCountDest.FavoritesCount, nil
+	return &isVisibleDest.IsVisible, nil
 }
 
 func (articlesService *ArticlesService) ListTags(ctx context.Context, listTags ListTags) (*[]model.ArticleTag, error) {
@@ -489,22 +733,6 @@ func (articlesService *ArticlesService) ListTags(ctx context.Context, listTags L
 	return &tags, nil
 }
 
-func (articlesService *ArticlesService) getTagByName(ctx context.Context, tagName string) (*model.ArticleTag, error) {
-	var tag model.ArticleTag
-
-	getTagByNameStmt := ArticleTag.SELECT(ArticleTag.AllColumns).WHERE(ArticleTag.Name.EQ(String(tagName)))
-
-	err := getTagByNameStmt.QueryContext(ctx, articlesService.db, &tag)
-	if err != nil {
-		if errors.Is(err, qrm.ErrNoRows) {
-			return nil, &NotFoundError{msg: fmt.Sprintf("Tag name %s not found", tagName)}
-		}
-		return nil, err
-	}
-
-	return &tag, nil
-}
-
 func (articlesService *ArticlesService) makeSlug(ctx context.Context, authorUsername string, title string) (*string, error) {
 	slug := slug.Make(fmt.Sprintf("%s %s", authorUsername, title))
 
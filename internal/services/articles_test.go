@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+const (
+	feedBenchmarkAuthors           = 500
+	feedBenchmarkFollowedAuthors   = 250
+	feedBenchmarkArticlesPerAuthor = 20
+)
+
+func TestFeed(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	reader := createTestUser(t, usersService)
+	followed := createTestUser(t, usersService)
+	suspended := createTestUser(t, usersService)
+	other := createTestUser(t, usersService)
+
+	articleIds := map[uuid.UUID]uuid.UUID{}
+
+	for _, author := range []*model.Users{followed, suspended, other} {
+		article, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, uniqueName(t, "Article "), "Description", "Body", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		articleIds[author.ID] = article.ID
+	}
+
+	for _, author := range []*model.Users{followed, suspended} {
+		if err := profilesService.FollowUser(ctx, reader.ID, author.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := usersService.SuspendUser(ctx, suspended.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	articles, articlesCount, err := articlesService.Feed(ctx, reader.ID, Feed{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*articles) != 1 || *articlesCount != 1 || (*articles)[0].ID != articleIds[followed.ID] {
+		t.Errorf("got %d articles out of %d, expected only the followed user's article", len(*articles), *articlesCount)
+	}
+
+	articles, _, err = articlesService.ListArticles(ctx, ListArticles{AuthorIDs: &[]uuid.UUID{suspended.ID, other.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*articles) != 1 || (*articles)[0].ID != articleIds[other.ID] {
+		t.Errorf("got %d articles, expected the suspended user's article to be left out", len(*articles))
+	}
+
+	isVisible, err := articlesService.IsArticleVisible(ctx, articleIds[suspended.ID], &reader.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *isVisible {
+		t.Error("expected the suspended user's article not to be visible")
+	}
+}
+
+// seedFeedBenchmark inserts the rows in bulk, since going through the services would take longer than the benchmark.
+func seedFeedBenchmark(b *testing.B, usersService *UsersService) uuid.UUID {
+	b.Helper()
+
+	ctx := context.Background()
+
+	users := make([]model.Users, feedBenchmarkAuthors+1)
+	for i := range users {
+		username := uniqueName(b, "feed-")
+
+		users[i] = model.Users{
+			Email:        username + "@example.com",
+			Username:     username,
+			PasswordHash: "not-a-password-hash",
+		}
+	}
+
+	insertUsersStmt := Users.INSERT(Users.Email, Users.Username, Users.PasswordHash).MODELS(users).RETURNING(Users.AllColumns)
+
+	if err := insertUsersStmt.QueryContext(ctx, usersService.db, &users); err != nil {
+		b.Fatal(err)
+	}
+
+	userIds := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		userIds[i] = user.ID
+	}
+
+	b.Cleanup(func() {
+		deleteUsersStmt := Users.DELETE().WHERE(Users.ID.IN(uuidExpressions(userIds)...))
+
+		if _, err := deleteUsersStmt.ExecContext(ctx, usersService.db); err != nil {
+			b.Error(err)
+		}
+	})
+
+	reader, authors := users[0], users[1:]
+
+	follows := make([]model.Follow, feedBenchmarkFollowedAuthors)
+	for i := range follows {
+		follows[i] = model.Follow{
+			FollowerID: &reader.ID,
+			FollowedID: &authors[i*len(authors)/feedBenchmarkFollowedAuthors].ID,
+		}
+	}
+
+	insertFollowsStmt := Follow.INSERT(Follow.FollowerID, Follow.FollowedID).MODELS(follows)
+
+	if _, err := insertFollowsStmt.ExecContext(ctx, usersService.db); err != nil {
+		b.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+
+	for i, author := range authors {
+		articles := make([]model.Article, feedBenchmarkArticlesPerAuthor)
+		for j := range articles {
+			createdAt := now.Add(-time.Duration(j*len(authors)+i) * time.Minute)
+
+			articles[j] = model.Article{
+				AuthorID:    &author.ID,
+				Slug:        fmt.Sprintf("%s-%d", author.Username, j),
+				Title:       fmt.Sprintf("Article %d by %s", j, author.Username),
+				Description: "Benchmark article",
+				Body:        "Benchmark article body.",
+				CreatedAt:   &createdAt,
+				UpdatedAt:   &createdAt,
+			}
+		}
+
+		insertArticlesStmt := Article.INSERT(Article.AuthorID, Article.Slug, Article.Title, Article.Description, Article.Body, Article.CreatedAt, Article.UpdatedAt).MODELS(articles)
+
+		if _, err := insertArticlesStmt.ExecContext(ctx, usersService.db); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	if _, err := usersService.db.ExecContext(ctx, "ANALYZE users, follow, article"); err != nil {
+		b.Fatal(err)
+	}
+
+	return reader.ID
+}
+
+// BenchmarkFeed compares passing the followed users' IDs to ListArticles, as the feed used to, with Feed.
+func BenchmarkFeed(b *testing.B) {
+	db := newTestDB(b)
+	usersService := newTestUsersService(b, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	readerId := seedFeedBenchmark(b, usersService)
+
+	ctx := context.Background()
+
+	limit := 20
+
+	b.Run("author IDs", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			followedProfiles, _, err := profilesService.ListFollowing(ctx, readerId, nil, ListFollows{})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			authorIds := make([]uuid.UUID, len(*followedProfiles))
+			for j, followedProfile := range *followedProfiles {
+				authorIds[j] = followedProfile.UserID
+			}
+
+			if _, _, err = articlesService.ListArticles(ctx, ListArticles{AuthorIDs: &authorIds, MutedByUserID: &readerId, Limit: &limit}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("join", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := articlesService.Feed(ctx, readerId, Feed{Limit: &limit}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestSearchArticlesEscapesHeadline(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	author := createTestUser(t, usersService)
+
+	keyword := uniqueName(t, "keyword")
+
+	article, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, "Escaping", "Escaping headlines", fmt.Sprintf(`<img src=x onerror="alert(1)"><b>%s</b> & <script>alert(1)</script>`, keyword), nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, _, err := articlesService.SearchArticles(ctx, keyword, ListArticles{AuthorIDs: &[]uuid.UUID{author.ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*results) != 1 || (*results)[0].Article.ID != article.ID {
+		t.Fatalf("got %d results, expected article %s", len(*results), article.ID)
+	}
+
+	headline := (*results)[0].Headline
+
+	if !strings.Contains(headline, "<mark>"+keyword+"</mark>") {
+		t.Errorf("headline %s doesn't highlight %s", headline, keyword)
+	}
+
+	// Apart from the highlighting, the headline must be plain text once it's unescaped.
+	if unhighlighted := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(headline); strings.ContainsAny(unhighlighted, `<>"`) {
+		t.Errorf("headline %s isn't escaped", headline)
+	}
+}
+
+func TestListArticlesFiltersAndSorts(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	author := createTestUser(t, usersService)
+	reader := createTestUser(t, usersService)
+
+	firstTag := uniqueName(t, "tag-")
+	secondTag := uniqueName(t, "tag-")
+
+	tagLists := [][]string{{firstTag}, {firstTag, secondTag}, {secondTag}}
+
+	articleIds := make([]uuid.UUID, len(tagLists))
+	for i, tagList := range tagLists {
+		article, err := articlesService.CreateArticle(ctx, NewCreateArticle(author.ID, uniqueName(t, "Article "), "Description", "Body", &tagList))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		articleIds[i] = article.ID
+	}
+
+	if err := articlesService.FavoriteArticle(ctx, reader.ID, articleIds[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	tagNames := []string{firstTag, secondTag}
+	mostFavorited := ArticleSortMostFavorited
+	oldest := ArticleSortOldest
+
+	testCases := []struct {
+		name         string
+		listArticles ListArticles
+		expectedIds  []uuid.UUID
+	}{
+		{
+			name:         "any tag",
+			listArticles: ListArticles{TagNames: &tagNames},
+			expectedIds:  []uuid.UUID{articleIds[2], articleIds[1], articleIds[0]},
+		},
+		{
+			name:         "all tags",
+			listArticles: ListArticles{TagNames: &tagNames, MatchAllTags: true},
+			expectedIds:  []uuid.UUID{articleIds[1]},
+		},
+		{
+			name:         "oldest",
+			listArticles: ListArticles{AuthorIDs: &[]uuid.UUID{author.ID}, Sort: &oldest},
+			expectedIds:  articleIds,
+		},
+		{
+			name:         "most favorited",
+			listArticles: ListArticles{AuthorIDs: &[]uuid.UUID{author.ID}, Sort: &mostFavorited},
+			expectedIds:  []uuid.UUID{articleIds[0], articleIds[2], articleIds[1]},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			articles, articlesCount, err := articlesService.ListArticles(ctx, testCase.listArticles)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ids := make([]uuid.UUID, len(*articles))
+			for i, article := range *articles {
+				ids[i] = article.ID
+			}
+
+			if !slices.Equal(ids, testCase.expectedIds) || *articlesCount != len(testCase.expectedIds) {
+				t.Errorf("got articles %v, expected %v", ids, testCase.expectedIds)
+			}
+		})
+	}
+}
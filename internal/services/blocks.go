@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// BlockUser removes the follows and follow requests between the users, in either direction.
+func (profilesService *ProfilesService) BlockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Blocking user", "blockerId", blockerId, "blockedId", blockedId)
+
+	if blockerId == blockedId {
+		return &InvalidArgumentError{msg: "Users cannot block themselves"}
+	}
+
+	blocked, err := profilesService.usersService.GetUserById(ctx, blockedId)
+	if err != nil {
+		return err
+	}
+
+	tx, err := profilesService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	blockStmt := Block.INSERT(Block.BlockerID, Block.BlockedID).VALUES(UUID(blockerId), UUID(blocked.ID)).ON_CONFLICT(Block.BlockerID, Block.BlockedID).DO_NOTHING()
+
+	if _, err = blockStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	deleteFollowsStmt := Follow.DELETE().WHERE(
+		Follow.FollowerID.EQ(UUID(blockerId)).AND(Follow.FollowedID.EQ(UUID(blocked.ID))).
+			OR(Follow.FollowerID.EQ(UUID(blocked.ID)).AND(Follow.FollowedID.EQ(UUID(blockerId)))))
+
+	if _, err = deleteFollowsStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	deleteFollowRequestsStmt := FollowRequest.DELETE().WHERE(
+		FollowRequest.FollowerID.EQ(UUID(blockerId)).AND(FollowRequest.FollowedID.EQ(UUID(blocked.ID))).
+			OR(FollowRequest.FollowerID.EQ(UUID(blocked.ID)).AND(FollowRequest.FollowedID.EQ(UUID(blockerId)))))
+
+	if _, err = deleteFollowRequestsStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (profilesService *ProfilesService) UnblockUser(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Unblocking user", "blockerId", blockerId, "blockedId", blockedId)
+
+	unblockStmt := Block.DELETE().WHERE(Block.BlockerID.EQ(UUID(blockerId)).AND(Block.BlockedID.EQ(UUID(blockedId))))
+
+	_, err := unblockStmt.ExecContext(ctx, profilesService.db)
+
+	return err
+}
+
+func (profilesService *ProfilesService) IsBlocking(ctx context.Context, blockerId uuid.UUID, blockedId uuid.UUID) (*bool, error) {
+	return isBlocking(ctx, profilesService.db, blockerId, blockedId)
+}
+
+func (profilesService *ProfilesService) ListBlockedProfiles(ctx context.Context, userId uuid.UUID) (*[]Profile, error) {
+	selectStmt := profilesService.selectProfiles(&userId).
+		FROM(Block.INNER_JOIN(Users, Users.ID.EQ(Block.BlockedID))).
+		WHERE(Block.BlockerID.EQ(UUID(userId))).
+		ORDER_BY(Block.CreatedAt.DESC(), Block.ID.DESC())
+
+	return profilesService.queryProfiles(ctx, selectStmt)
+}
+
+func isBlocking(ctx context.Context, db qrm.Queryable, blockerId uuid.UUID, blockedId uuid.UUID) (*bool, error) {
+	var dest struct {
+		IsBlocking bool
+	}
+
+	isBlockingStmt := SELECT(EXISTS(Block.SELECT(Block.ID).WHERE(Block.BlockerID.EQ(UUID(blockerId)).AND(Block.BlockedID.EQ(UUID(blockedId))))).AS("is_blocking"))
+
+	if err := isBlockingStmt.QueryContext(ctx, db, &dest); err != nil {
+		return nil, err
+	}
+
+	return &dest.IsBlocking, nil
+}
+
+func isBlockedBetween(userId StringExpression, otherUserId StringExpression) BoolExpression {
+	return EXISTS(Block.SELECT(Block.ID).WHERE(
+		Block.BlockerID.EQ(userId).AND(Block.BlockedID.EQ(otherUserId)).
+			OR(Block.BlockerID.EQ(otherUserId).AND(Block.BlockedID.EQ(userId)))))
+}
+
+func checkNotBlocked(ctx context.Context, db qrm.Queryable, blockerId uuid.UUID, blockedId uuid.UUID) error {
+	blocking, err := isBlocking(ctx, db, blockerId, blockedId)
+	if err != nil {
+		return err
+	}
+
+	if *blocking {
+		return &PermissionDeniedError{msg: fmt.Sprintf("User %s can't interact with user %s", blockedId, blockerId)}
+	}
+
+	return nil
+}
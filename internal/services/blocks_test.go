@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBlockUser(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+	articlesService := NewArticlesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	blocker := createTestUser(t, usersService)
+	blocked := createTestUser(t, usersService)
+
+	if err := profilesService.FollowUser(ctx, blocker.ID, blocked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := profilesService.FollowUser(ctx, blocked.ID, blocker.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	article, err := articlesService.CreateArticle(ctx, NewCreateArticle(blocker.ID, uniqueName(t, "Article "), "Description", "Body", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = profilesService.BlockUser(ctx, blocker.ID, blocked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	isFollowing, err := profilesService.IsFollowing(ctx, blocker.ID, blocked.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isFollowed, err := profilesService.IsFollowing(ctx, blocked.ID, blocker.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *isFollowing || *isFollowed {
+		t.Errorf("got following %t and followed %t, expected the follows to be removed", *isFollowing, *isFollowed)
+	}
+
+	var permissionDeniedError *PermissionDeniedError
+
+	if err = profilesService.FollowUser(ctx, blocked.ID, blocker.ID); !errors.As(err, &permissionDeniedError) {
+		t.Errorf("got error %v following the blocker, expected a PermissionDeniedError", err)
+	}
+
+	if _, err = articlesService.CreateComment(ctx, article.ID, blocked.ID, "Comment"); !errors.As(err, &permissionDeniedError) {
+		t.Errorf("got error %v commenting on the blocker's article, expected a PermissionDeniedError", err)
+	}
+
+	if err = profilesService.UnblockUser(ctx, blocker.ID, blocked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = profilesService.FollowUser(ctx, blocked.ID, blocker.ID); err != nil {
+		t.Errorf("got error %v following after being unblocked", err)
+	}
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+)
+
+// Cursor is the position of an item in a list ordered by creation time, most recent first.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func NewCursor(createdAt time.Time, id uuid.UUID) Cursor {
+	return Cursor{
+		CreatedAt: createdAt,
+		ID:        id,
+	}
+}
+
+func ParseCursor(cursor string) (*Cursor, error) {
+	invalidCursorErr := &InvalidArgumentError{msg: fmt.Sprintf("Invalid cursor %s", cursor)}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalidCursorErr
+	}
+
+	createdAtValue, idValue, found := strings.Cut(string(decoded), ",")
+	if !found {
+		return nil, invalidCursorErr
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtValue)
+	if err != nil {
+		return nil, invalidCursorErr
+	}
+
+	id, err := uuid.Parse(idValue)
+	if err != nil {
+		return nil, invalidCursorErr
+	}
+
+	parsedCursor := NewCursor(createdAt, id)
+
+	return &parsedCursor, nil
+}
+
+func (cursor Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + cursor.ID.String()))
+}
+
+func (cursor Cursor) after(createdAt ColumnTimestampz, id ColumnString) BoolExpression {
+	return createdAt.LT(TimestampzT(cursor.CreatedAt)).
+		OR(createdAt.EQ(TimestampzT(cursor.CreatedAt)).AND(id.LT(UUID(cursor.ID))))
+}
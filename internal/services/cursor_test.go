@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseCursor(t *testing.T) {
+	cursor := NewCursor(time.Date(2024, 5, 17, 10, 30, 0, 123456789, time.UTC), uuid.New())
+
+	parsedCursor, err := ParseCursor(cursor.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !parsedCursor.CreatedAt.Equal(cursor.CreatedAt) || parsedCursor.ID != cursor.ID {
+		t.Errorf("got cursor %v, expected %v", *parsedCursor, cursor)
+	}
+
+	invalidCursors := []string{"", "not base64!", "bm8gY29tbWE", "MjAyNCxub3QtYS11dWlk"}
+
+	for _, invalidCursor := range invalidCursors {
+		var invalidArgumentError *InvalidArgumentError
+
+		if _, err := ParseCursor(invalidCursor); !errors.As(err, &invalidArgumentError) {
+			t.Errorf("got error %v parsing %q, expected an InvalidArgumentError", err, invalidCursor)
+		}
+	}
+}
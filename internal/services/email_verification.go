@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// RequestEmailVerification does nothing if the email is already verified.
+func (usersService *UsersService) RequestEmailVerification(ctx context.Context, userId uuid.UUID) error {
+	user, err := usersService.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	usersService.logger.InfoContext(ctx, "Requesting email verification", "userId", user.ID, "email", user.Email)
+
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	emailVerificationToken := model.EmailVerificationToken{
+		UserID:    &user.ID,
+		Email:     user.Email,
+		TokenHash: hashToken(*token),
+		ExpiresAt: time.Now().UTC().Add(time.Second * time.Duration(usersService.config.EmailVerificationTokenValidForSeconds)),
+	}
+
+	insertTokenStmt := EmailVerificationToken.INSERT(EmailVerificationToken.UserID, EmailVerificationToken.Email, EmailVerificationToken.TokenHash, EmailVerificationToken.ExpiresAt).MODEL(emailVerificationToken)
+
+	if _, err = insertTokenStmt.ExecContext(ctx, usersService.db); err != nil {
+		return err
+	}
+
+	verifyUrl := fmt.Sprintf("%s/verify-email?token=%s", usersService.config.AppURL, url.QueryEscape(*token))
+
+	return usersService.mailer.Send(ctx, Mail{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm that %s is your email by opening the link below:\n\n%s\n\nThe link expires at %s.\n",
+			user.Username, user.Email, verifyUrl, emailVerificationToken.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+// VerifyEmail rejects tokens sent to a previous email of the user.
+func (usersService *UsersService) VerifyEmail(ctx context.Context, token string) (*model.Users, error) {
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var emailVerificationToken model.EmailVerificationToken
+
+	getTokenStmt := SELECT(EmailVerificationToken.AllColumns).FROM(EmailVerificationToken).WHERE(EmailVerificationToken.TokenHash.EQ(String(hashToken(token)))).FOR(UPDATE())
+
+	err = getTokenStmt.QueryContext(ctx, tx, &emailVerificationToken)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &InvalidArgumentError{msg: "Invalid email verification token"}
+		}
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if emailVerificationToken.UsedAt != nil || now.After(emailVerificationToken.ExpiresAt) {
+		return nil, &InvalidArgumentError{msg: "Email verification token is expired or has already been used"}
+	}
+
+	user, err := usersService.GetUserById(ctx, *emailVerificationToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Email != emailVerificationToken.Email {
+		return nil, &InvalidArgumentError{msg: "Email verification token was issued for a previous email"}
+	}
+
+	usersService.logger.InfoContext(ctx, "Verifying email", "userId", user.ID, "email", user.Email)
+
+	markTokenUsedStmt := EmailVerificationToken.UPDATE(EmailVerificationToken.UsedAt).SET(TimestampzT(now)).WHERE(EmailVerificationToken.ID.EQ(UUID(emailVerificationToken.ID)))
+
+	if _, err = markTokenUsedStmt.ExecContext(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	user.EmailVerifiedAt = &now
+
+	verifyEmailStmt := Users.UPDATE(Users.EmailVerifiedAt).MODEL(user).WHERE(Users.ID.EQ(UUID(user.ID)))
+
+	if _, err = verifyEmailStmt.ExecContext(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -1,5 +1,16 @@
 package services
 
+import "time"
+
+type AccountLockedError struct {
+	msg        string
+	RetryAfter time.Duration
+}
+
+func (err *AccountLockedError) Error() string {
+	return err.msg
+}
+
 type AlreadyExistsError struct {
 	msg string
 }
@@ -23,3 +34,28 @@ type NotFoundError struct {
 func (err *NotFoundError) Error() string {
 	return err.msg
 }
+
+type PermissionDeniedError struct {
+	msg string
+}
+
+func (err *PermissionDeniedError) Error() string {
+	return err.msg
+}
+
+type TooManyRequestsError struct {
+	msg        string
+	RetryAfter time.Duration
+}
+
+func (err *TooManyRequestsError) Error() string {
+	return err.msg
+}
+
+type UnauthenticatedError struct {
+	msg string
+}
+
+func (err *UnauthenticatedError) Error() string {
+	return err.msg
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+func (profilesService *ProfilesService) ListFollowRequests(ctx context.Context, userId uuid.UUID) (*[]Profile, error) {
+	selectStmt := profilesService.selectProfiles(&userId).
+		FROM(FollowRequest.INNER_JOIN(Users, Users.ID.EQ(FollowRequest.FollowerID))).
+		WHERE(FollowRequest.FollowedID.EQ(UUID(userId))).
+		ORDER_BY(FollowRequest.CreatedAt.ASC(), FollowRequest.ID.ASC())
+
+	return profilesService.queryProfiles(ctx, selectStmt)
+}
+
+func (profilesService *ProfilesService) ApproveFollowRequest(ctx context.Context, followedId uuid.UUID, followerId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Approving follow request", "followedId", followedId, "followerId", followerId)
+
+	tx, err := profilesService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = checkNotBlocked(ctx, tx, followedId, followerId); err != nil {
+		return err
+	}
+
+	if err = checkNotBlocked(ctx, tx, followerId, followedId); err != nil {
+		return err
+	}
+
+	followRequest := model.FollowRequest{}
+
+	deleteRequestStmt := FollowRequest.DELETE().WHERE(FollowRequest.FollowerID.EQ(UUID(followerId)).AND(FollowRequest.FollowedID.EQ(UUID(followedId)))).RETURNING(FollowRequest.AllColumns)
+
+	if err = deleteRequestStmt.QueryContext(ctx, tx, &followRequest); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return &NotFoundError{msg: fmt.Sprintf("Follow request from user %s not found", followerId)}
+		}
+		return err
+	}
+
+	followStmt := Follow.INSERT(Follow.FollowerID, Follow.FollowedID).VALUES(UUID(followerId), UUID(followedId)).ON_CONFLICT(Follow.FollowerID, Follow.FollowedID).DO_NOTHING()
+
+	if _, err = followStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (profilesService *ProfilesService) RemoveFollower(ctx context.Context, followedId uuid.UUID, followerId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Removing follower", "followedId", followedId, "followerId", followerId)
+
+	if err := profilesService.cancelFollowRequest(ctx, followerId, followedId); err != nil {
+		return err
+	}
+
+	removeFollowerStmt := Follow.DELETE().WHERE(Follow.FollowerID.EQ(UUID(followerId)).AND(Follow.FollowedID.EQ(UUID(followedId))))
+
+	_, err := removeFollowerStmt.ExecContext(ctx, profilesService.db)
+
+	return err
+}
+
+func (profilesService *ProfilesService) CanView(ctx context.Context, viewerId *uuid.UUID, user model.Users) (*bool, error) {
+	canView := !user.IsPrivate || (viewerId != nil && *viewerId == user.ID)
+	if canView || viewerId == nil {
+		return &canView, nil
+	}
+
+	return profilesService.IsFollowing(ctx, *viewerId, user.ID)
+}
+
+func (profilesService *ProfilesService) requestFollow(ctx context.Context, followerId uuid.UUID, followedId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Requesting to follow user", "followerId", followerId, "followedId", followedId)
+
+	requestStmt := FollowRequest.INSERT(FollowRequest.FollowerID, FollowRequest.FollowedID).VALUES(UUID(followerId), UUID(followedId)).ON_CONFLICT(FollowRequest.FollowerID, FollowRequest.FollowedID).DO_NOTHING()
+
+	_, err := requestStmt.ExecContext(ctx, profilesService.db)
+
+	return err
+}
+
+func (profilesService *ProfilesService) cancelFollowRequest(ctx context.Context, followerId uuid.UUID, followedId uuid.UUID) error {
+	cancelStmt := FollowRequest.DELETE().WHERE(FollowRequest.FollowerID.EQ(UUID(followerId)).AND(FollowRequest.FollowedID.EQ(UUID(followedId))))
+
+	_, err := cancelStmt.ExecContext(ctx, profilesService.db)
+
+	return err
+}
+
+// approveFollowRequests drops the requests from users blocked by or blocking the user.
+func approveFollowRequests(ctx context.Context, db qrm.Executable, userId uuid.UUID) error {
+	approveStmt := Follow.INSERT(Follow.FollowerID, Follow.FollowedID).
+		QUERY(SELECT(FollowRequest.FollowerID, FollowRequest.FollowedID).FROM(FollowRequest).WHERE(FollowRequest.FollowedID.EQ(UUID(userId)).AND(NOT(isBlockedBetween(UUID(userId), FollowRequest.FollowerID))))).
+		ON_CONFLICT(Follow.FollowerID, Follow.FollowedID).DO_NOTHING()
+
+	if _, err := approveStmt.ExecContext(ctx, db); err != nil {
+		return err
+	}
+
+	deleteRequestsStmt := FollowRequest.DELETE().WHERE(FollowRequest.FollowedID.EQ(UUID(userId)))
+
+	_, err := deleteRequestsStmt.ExecContext(ctx, db)
+
+	return err
+}
+
+func isVisibleTo(authorId ColumnString, viewerId *uuid.UUID) BoolExpression {
+	visible := authorId.NOT_IN(SELECT(Users.ID).FROM(Users).WHERE(Users.IsPrivate.IS_TRUE()))
+
+	if viewerId != nil {
+		visible = visible.
+			OR(authorId.EQ(UUID(*viewerId))).
+			OR(authorId.IN(SELECT(Follow.FollowedID).FROM(Follow).WHERE(Follow.FollowerID.EQ(UUID(*viewerId)))))
+	}
+
+	return visible
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+func createTestPrivateUser(t *testing.T, usersService *UsersService) *model.Users {
+	t.Helper()
+
+	user := createTestUser(t, usersService)
+
+	isPrivate := true
+
+	user, err := usersService.UpdateUser(context.Background(), user.ID, UpdateUser{IsPrivate: &isPrivate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user
+}
+
+func TestBlockUserRemovesFollowRequests(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	privateUser := createTestPrivateUser(t, usersService)
+	requester := createTestUser(t, usersService)
+
+	if err := profilesService.FollowUser(ctx, requester.ID, privateUser.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := profilesService.BlockUser(ctx, requester.ID, privateUser.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	followRequests, err := profilesService.ListFollowRequests(ctx, privateUser.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*followRequests) != 0 {
+		t.Errorf("got %d follow requests, expected the block to remove them", len(*followRequests))
+	}
+}
+
+func TestApproveFollowRequestChecksBlocks(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	privateUser := createTestPrivateUser(t, usersService)
+	requester := createTestUser(t, usersService)
+
+	if err := profilesService.FollowUser(ctx, requester.ID, privateUser.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	blockStmt := Block.INSERT(Block.BlockerID, Block.BlockedID).VALUES(UUID(requester.ID), UUID(privateUser.ID))
+
+	if _, err := blockStmt.ExecContext(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var permissionDeniedError *PermissionDeniedError
+
+	if err := profilesService.ApproveFollowRequest(ctx, privateUser.ID, requester.ID); !errors.As(err, &permissionDeniedError) {
+		t.Errorf("got error %v approving a blocked request, expected a PermissionDeniedError", err)
+	}
+
+	isPrivate := false
+
+	if _, err := usersService.UpdateUser(ctx, privateUser.ID, UpdateUser{IsPrivate: &isPrivate}); err != nil {
+		t.Fatal(err)
+	}
+
+	isFollowing, err := profilesService.IsFollowing(ctx, requester.ID, privateUser.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *isFollowing {
+		t.Error("expected the blocked request not to be approved when the user stopped being private")
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type UsersServiceJWT struct {
+	iss                         string
+	keys                        []JWTKey
+	parser                      jwt.Parser
+	validForSeconds             int
+	refreshTokenValidForSeconds int
+}
+
+type JWTKey struct {
+	ID            string
+	SigningMethod jwt.SigningMethod
+	ActiveFrom    time.Time
+	signingKey    interface{}
+	verifyingKey  interface{}
+}
+
+type JSONWebKey struct {
+	KeyType   string
+	KeyID     string
+	Algorithm string
+	Use       string
+	N         string
+	E         string
+	Curve     string
+	X         string
+}
+
+// NewUsersServiceJWT signs tokens with the most recently activated key and verifies them with any of the keys.
+func NewUsersServiceJWT(iss string, keys []JWTKey, validForSeconds int, refreshTokenValidForSeconds int) (UsersServiceJWT, error) {
+	if len(keys) == 0 {
+		return UsersServiceJWT{}, errors.New("at least one JWT key is required")
+	}
+
+	keyIds := map[string]bool{}
+	validMethods := map[string]bool{}
+
+	for _, key := range keys {
+		if keyIds[key.ID] {
+			return UsersServiceJWT{}, fmt.Errorf("duplicate JWT key ID %s", key.ID)
+		}
+		keyIds[key.ID] = true
+		validMethods[key.SigningMethod.Alg()] = true
+	}
+
+	var validMethodNames []string
+	for validMethod := range validMethods {
+		validMethodNames = append(validMethodNames, validMethod)
+	}
+
+	sortedKeys := make([]JWTKey, len(keys))
+	copy(sortedKeys, keys)
+
+	sort.SliceStable(sortedKeys, func(i, j int) bool {
+		return sortedKeys[i].ActiveFrom.After(sortedKeys[j].ActiveFrom)
+	})
+
+	return UsersServiceJWT{
+		iss:                         iss,
+		keys:                        sortedKeys,
+		parser:                      *jwt.NewParser(jwt.WithValidMethods(validMethodNames), jwt.WithExpirationRequired(), jwt.WithIssuedAt(), jwt.WithIssuer(iss)),
+		validForSeconds:             validForSeconds,
+		refreshTokenValidForSeconds: refreshTokenValidForSeconds,
+	}, nil
+}
+
+func NewHMACJWTKey(id string, secret []byte, activeFrom time.Time) JWTKey {
+	return JWTKey{
+		ID:            id,
+		SigningMethod: jwt.SigningMethodHS256,
+		ActiveFrom:    activeFrom,
+		signingKey:    secret,
+		verifyingKey:  secret,
+	}
+}
+
+// LoadJWTKey loads an RSA or Ed25519 key from a PEM file. Public keys can only verify tokens.
+func LoadJWTKey(id string, path string, activeFrom time.Time) (*JWTKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	var parsedKey interface{}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsedKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsedKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsedKey, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsedKey, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %s in %s", block.Type, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := JWTKey{
+		ID:         id,
+		ActiveFrom: activeFrom,
+	}
+
+	switch k := parsedKey.(type) {
+	case *rsa.PrivateKey:
+		key.SigningMethod = jwt.SigningMethodRS256
+		key.signingKey = k
+		key.verifyingKey = &k.PublicKey
+	case *rsa.PublicKey:
+		key.SigningMethod = jwt.SigningMethodRS256
+		key.verifyingKey = k
+	case ed25519.PrivateKey:
+		key.SigningMethod = jwt.SigningMethodEdDSA
+		key.signingKey = k
+		key.verifyingKey = k.Public()
+	case ed25519.PublicKey:
+		key.SigningMethod = jwt.SigningMethodEdDSA
+		key.verifyingKey = k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T in %s", parsedKey, path)
+	}
+
+	return &key, nil
+}
+
+func (usersService *UsersService) JSONWebKeySet() []JSONWebKey {
+	return usersService.jwt.jsonWebKeySet()
+}
+
+func (usersServiceJWT *UsersServiceJWT) jsonWebKeySet() []JSONWebKey {
+	jsonWebKeys := []JSONWebKey{}
+
+	for _, key := range usersServiceJWT.keys {
+		switch verifyingKey := key.verifyingKey.(type) {
+		case *rsa.PublicKey:
+			jsonWebKeys = append(jsonWebKeys, JSONWebKey{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Algorithm: key.SigningMethod.Alg(),
+				Use:       "sig",
+				N:         base64.RawURLEncoding.EncodeToString(verifyingKey.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(verifyingKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jsonWebKeys = append(jsonWebKeys, JSONWebKey{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Algorithm: key.SigningMethod.Alg(),
+				Use:       "sig",
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(verifyingKey),
+			})
+		}
+	}
+
+	return jsonWebKeys
+}
+
+func (usersServiceJWT *UsersServiceJWT) sign(claims jwt.Claims) (*string, error) {
+	now := time.Now()
+
+	for _, key := range usersServiceJWT.keys {
+		if key.signingKey == nil || key.ActiveFrom.After(now) {
+			continue
+		}
+
+		token := jwt.NewWithClaims(key.SigningMethod, claims)
+		token.Header["kid"] = key.ID
+
+		signedToken, err := token.SignedString(key.signingKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return &signedToken, nil
+	}
+
+	return nil, errors.New("no active JWT signing key")
+}
+
+func (usersServiceJWT *UsersServiceJWT) parse(token string, claims jwt.Claims) error {
+	_, err := usersServiceJWT.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		keyId, _ := t.Header["kid"].(string)
+
+		if keyId == "" && len(usersServiceJWT.keys) == 1 {
+			keyId = usersServiceJWT.keys[0].ID
+		}
+
+		for _, key := range usersServiceJWT.keys {
+			if key.ID != keyId {
+				continue
+			}
+
+			if t.Method.Alg() != key.SigningMethod.Alg() {
+				return nil, fmt.Errorf("token algorithm %s does not match key %s", t.Method.Alg(), key.ID)
+			}
+
+			return key.verifyingKey, nil
+		}
+
+		return nil, fmt.Errorf("unknown JWT key ID %s", keyId)
+	})
+
+	return err
+}
@@ -0,0 +1,274 @@
+package services
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writeTestPEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadJWTKey(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ed25519PublicKey, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8RSAKey, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8Ed25519Key, err := x509.MarshalPKCS8PrivateKey(ed25519Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkcs8ECDSAKey, err := x509.MarshalPKCS8PrivateKey(ecdsaKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkixRSAPublicKey, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkixEd25519PublicKey, err := x509.MarshalPKIXPublicKey(ed25519PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name      string
+		blockType string
+		der       []byte
+		alg       string
+		canSign   bool
+		wantErr   bool
+	}{
+		{name: "PKCS #1 RSA private key", blockType: "RSA PRIVATE KEY", der: x509.MarshalPKCS1PrivateKey(rsaKey), alg: "RS256", canSign: true},
+		{name: "PKCS #8 RSA private key", blockType: "PRIVATE KEY", der: pkcs8RSAKey, alg: "RS256", canSign: true},
+		{name: "PKCS #8 Ed25519 private key", blockType: "PRIVATE KEY", der: pkcs8Ed25519Key, alg: "EdDSA", canSign: true},
+		{name: "PKCS #1 RSA public key", blockType: "RSA PUBLIC KEY", der: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey), alg: "RS256"},
+		{name: "PKIX RSA public key", blockType: "PUBLIC KEY", der: pkixRSAPublicKey, alg: "RS256"},
+		{name: "PKIX Ed25519 public key", blockType: "PUBLIC KEY", der: pkixEd25519PublicKey, alg: "EdDSA"},
+		{name: "ECDSA private key", blockType: "PRIVATE KEY", der: pkcs8ECDSAKey, wantErr: true},
+		{name: "unsupported block type", blockType: "CERTIFICATE", der: []byte("not a certificate"), wantErr: true},
+		{name: "corrupted key", blockType: "PRIVATE KEY", der: []byte("not a key"), wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			activeFrom := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+			key, err := LoadJWTKey("kid", writeTestPEM(t, testCase.blockType, testCase.der), activeFrom)
+			if testCase.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if key.ID != "kid" || !key.ActiveFrom.Equal(activeFrom) {
+				t.Errorf("got key %s active from %s", key.ID, key.ActiveFrom)
+			}
+
+			if key.SigningMethod.Alg() != testCase.alg {
+				t.Errorf("got algorithm %s, expected %s", key.SigningMethod.Alg(), testCase.alg)
+			}
+
+			if canSign := key.signingKey != nil; canSign != testCase.canSign {
+				t.Errorf("got canSign %t, expected %t", canSign, testCase.canSign)
+			}
+		})
+	}
+}
+
+func TestLoadJWTKeyNotPEM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+
+	if err := os.WriteFile(path, []byte("not PEM"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadJWTKey("kid", path, time.Time{}); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestNewUsersServiceJWTValidatesKeys(t *testing.T) {
+	if _, err := NewUsersServiceJWT("test", nil, 900, 3600); err == nil {
+		t.Error("expected an error without keys")
+	}
+
+	keys := []JWTKey{NewHMACJWTKey("kid", []byte("secret"), time.Time{}), NewHMACJWTKey("kid", []byte("other-secret"), time.Time{})}
+
+	if _, err := NewUsersServiceJWT("test", keys, 900, 3600); err == nil {
+		t.Error("expected an error with duplicate key IDs")
+	}
+}
+
+func newTestClaims() jwt.RegisteredClaims {
+	now := time.Now()
+
+	return jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		IssuedAt:  jwt.NewNumericDate(now),
+		Issuer:    "test",
+	}
+}
+
+func signTestToken(t *testing.T, signingMethod jwt.SigningMethod, keyId string, signingKey interface{}) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(signingMethod, newTestClaims())
+	if keyId != "" {
+		token.Header["kid"] = keyId
+	}
+
+	signedToken, err := token.SignedString(signingKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signedToken
+}
+
+func TestUsersServiceJWTParseBindsAlgorithmToKeyID(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaPublicKeyDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []JWTKey{
+		{ID: "rsa", SigningMethod: jwt.SigningMethodRS256, signingKey: rsaKey, verifyingKey: &rsaKey.PublicKey},
+		{ID: "ed25519", SigningMethod: jwt.SigningMethodEdDSA, signingKey: ed25519Key, verifyingKey: ed25519Key.Public()},
+		NewHMACJWTKey("hmac", []byte("secret"), time.Time{}),
+	}
+
+	usersServiceJWT, err := NewUsersServiceJWT("test", keys, 900, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "RS256 with the RSA key", token: signTestToken(t, jwt.SigningMethodRS256, "rsa", rsaKey)},
+		{name: "EdDSA with the Ed25519 key", token: signTestToken(t, jwt.SigningMethodEdDSA, "ed25519", ed25519Key)},
+		{name: "HS256 with the HMAC key", token: signTestToken(t, jwt.SigningMethodHS256, "hmac", []byte("secret"))},
+		{name: "RS256 with the Ed25519 key ID", token: signTestToken(t, jwt.SigningMethodRS256, "ed25519", rsaKey), wantErr: true},
+		{name: "HS256 keyed with the RSA public key", token: signTestToken(t, jwt.SigningMethodHS256, "rsa", rsaPublicKeyDER), wantErr: true},
+		{name: "unknown key ID", token: signTestToken(t, jwt.SigningMethodRS256, "other", rsaKey), wantErr: true},
+		{name: "no key ID", token: signTestToken(t, jwt.SigningMethodRS256, "", rsaKey), wantErr: true},
+		{name: "none algorithm", token: signTestToken(t, jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType), wantErr: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := usersServiceJWT.parse(testCase.token, &jwt.RegisteredClaims{})
+			if testCase.wantErr && err == nil {
+				t.Error("expected an error")
+			}
+			if !testCase.wantErr && err != nil {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
+
+func TestUsersServiceJWTParseWithoutKeyIDUsesTheOnlyKey(t *testing.T) {
+	usersServiceJWT, err := NewUsersServiceJWT("test", []JWTKey{NewHMACJWTKey("hmac", []byte("secret"), time.Time{})}, 900, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = usersServiceJWT.parse(signTestToken(t, jwt.SigningMethodHS256, "", []byte("secret")), &jwt.RegisteredClaims{}); err != nil {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestUsersServiceJWTSignUsesLatestActiveKey(t *testing.T) {
+	now := time.Now()
+
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []JWTKey{
+		NewHMACJWTKey("old", []byte("old-secret"), now.Add(-48*time.Hour)),
+		NewHMACJWTKey("current", []byte("current-secret"), now.Add(-24*time.Hour)),
+		NewHMACJWTKey("next", []byte("next-secret"), now.Add(24*time.Hour)),
+		{ID: "verify-only", SigningMethod: jwt.SigningMethodEdDSA, ActiveFrom: now.Add(-time.Hour), verifyingKey: ed25519Key.Public()},
+	}
+
+	usersServiceJWT, err := NewUsersServiceJWT("test", keys, 900, 3600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signedToken, err := usersServiceJWT.sign(newTestClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, _, err := jwt.NewParser().ParseUnverified(*signedToken, &jwt.RegisteredClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keyId := token.Header["kid"]; keyId != "current" {
+		t.Errorf("got key ID %v, expected current", keyId)
+	}
+
+	if err = usersServiceJWT.parse(*signedToken, &jwt.RegisteredClaims{}); err != nil {
+		t.Errorf("got error %v", err)
+	}
+}
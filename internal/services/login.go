@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+const (
+	loginFailedAttemptsWindow   = 15 * time.Minute
+	loginMaxFailedAttempts      = 5
+	accountLockoutDuration      = time.Minute
+	accountLockoutMaxDuration   = time.Hour
+	accountLockoutBackoffWindow = 24 * time.Hour
+	loginIPMaxFailedAttempts    = 20
+)
+
+// Login checks the password before the lockout, so wrong passwords get the same error as unknown emails.
+func (usersService *UsersService) Login(ctx context.Context, email string, password string, ipAddress string) (*model.Users, error) {
+	now := time.Now().UTC()
+
+	if err := usersService.checkIPAddressFailedLogins(ctx, ipAddress, now); err != nil {
+		return nil, err
+	}
+
+	user, err := usersService.GetUserByEmail(ctx, email)
+	if err != nil {
+		var notFoundError *NotFoundError
+		if errors.As(err, &notFoundError) {
+			if err = usersService.hashDummyPassword(password); err != nil {
+				return nil, err
+			}
+
+			if err = usersService.recordLoginAttempt(ctx, nil, email, ipAddress, false); err != nil {
+				return nil, err
+			}
+			return nil, &UnauthenticatedError{msg: notFoundError.Error()}
+		}
+		return nil, err
+	}
+
+	isCorrectPassword, err := usersService.CheckPassword(ctx, user.ID, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if !*isCorrectPassword {
+		if err = usersService.recordFailedLogin(ctx, user, ipAddress, now); err != nil {
+			return nil, err
+		}
+
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("Incorrect password for user %s", user.ID)}
+	}
+
+	if err = usersService.checkAccountLockout(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+
+	isTwoFactorEnabled, err := usersService.IsTwoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A successful login resets the failed logins, so it can't be recorded before the second factor is checked.
+	if !*isTwoFactorEnabled {
+		if err = usersService.recordLoginAttempt(ctx, &user.ID, email, ipAddress, true); err != nil {
+			return nil, err
+		}
+	}
+
+	return user, nil
+}
+
+func (usersService *UsersService) UnlockAccount(ctx context.Context, userId uuid.UUID, unlockedBy *uuid.UUID) error {
+	usersService.logger.InfoContext(ctx, "Unlocking user", "userId", userId, "unlockedBy", unlockedBy)
+
+	now := time.Now().UTC()
+
+	unlockedByExpression := StringExp(NULL)
+	if unlockedBy != nil {
+		unlockedByExpression = UUID(*unlockedBy)
+	}
+
+	unlockStmt := AccountLockout.UPDATE(AccountLockout.UnlockedAt, AccountLockout.UnlockedBy).SET(TimestampzT(now), unlockedByExpression).WHERE(AccountLockout.UserID.EQ(UUID(userId)).AND(AccountLockout.UnlockedAt.IS_NULL()).AND(AccountLockout.LockedUntil.GT(TimestampzT(now))))
+
+	_, err := unlockStmt.ExecContext(ctx, usersService.db)
+
+	return err
+}
+
+func (usersService *UsersService) ListAccountLockouts(ctx context.Context, userId uuid.UUID) (*[]model.AccountLockout, error) {
+	lockouts := []model.AccountLockout{}
+
+	listStmt := SELECT(AccountLockout.AllColumns).FROM(AccountLockout).WHERE(AccountLockout.UserID.EQ(UUID(userId))).ORDER_BY(AccountLockout.CreatedAt.DESC())
+
+	if err := listStmt.QueryContext(ctx, usersService.db, &lockouts); err != nil {
+		return nil, err
+	}
+
+	return &lockouts, nil
+}
+
+// hashDummyPassword takes as long as checking a password, so unknown emails can't be told apart by timing.
+func (usersService *UsersService) hashDummyPassword(password string) error {
+	_, err := usersService.passwordHasher.Hash(password)
+
+	return err
+}
+
+func (usersService *UsersService) checkAccountLockout(ctx context.Context, userId uuid.UUID, now time.Time) error {
+	lockouts, err := usersService.listRecentAccountLockouts(ctx, userId, now)
+	if err != nil {
+		return err
+	}
+
+	if len(*lockouts) == 0 {
+		return nil
+	}
+
+	lastLockout := (*lockouts)[0]
+
+	if lastLockout.UnlockedAt != nil || !now.Before(lastLockout.LockedUntil) {
+		return nil
+	}
+
+	return &AccountLockedError{
+		msg:        fmt.Sprintf("User %s is locked until %s", userId, lastLockout.LockedUntil.Format(time.RFC3339)),
+		RetryAfter: lastLockout.LockedUntil.Sub(now),
+	}
+}
+
+// recordFailedLogin locks the user once they failed too many times since their last lockout.
+func (usersService *UsersService) recordFailedLogin(ctx context.Context, user *model.Users, ipAddress string, now time.Time) error {
+	if err := usersService.recordLoginAttempt(ctx, &user.ID, user.Email, ipAddress, false); err != nil {
+		return err
+	}
+
+	lockouts, err := usersService.listRecentAccountLockouts(ctx, user.ID, now)
+	if err != nil {
+		return err
+	}
+
+	failedAttemptsSince := now.Add(-loginFailedAttemptsWindow)
+
+	if len(*lockouts) > 0 {
+		lastLockout := (*lockouts)[0]
+
+		if lastLockout.UnlockedAt == nil && now.Before(lastLockout.LockedUntil) {
+			return nil
+		}
+
+		if lastLockout.CreatedAt.After(failedAttemptsSince) {
+			failedAttemptsSince = *lastLockout.CreatedAt
+		}
+	}
+
+	failedAttempts, err := usersService.countFailedLogins(ctx, user.ID, failedAttemptsSince)
+	if err != nil {
+		return err
+	}
+
+	if *failedAttempts >= loginMaxFailedAttempts {
+		return usersService.lockAccount(ctx, user.ID, *failedAttempts, ipAddress, *lockouts, now)
+	}
+
+	return nil
+}
+
+func (usersService *UsersService) checkIPAddressFailedLogins(ctx context.Context, ipAddress string, now time.Time) error {
+	var dest struct {
+		FailedAttempts int
+		FirstAttemptAt *time.Time
+	}
+
+	countStmt := SELECT(COUNT(STAR).AS("failed_attempts"), MIN(LoginAttempt.CreatedAt).AS("first_attempt_at")).FROM(LoginAttempt).WHERE(LoginAttempt.IPAddress.EQ(String(ipAddress)).AND(LoginAttempt.Succeeded.IS_FALSE()).AND(LoginAttempt.CreatedAt.GT(TimestampzT(now.Add(-loginFailedAttemptsWindow)))))
+
+	if err := countStmt.QueryContext(ctx, usersService.db, &dest); err != nil {
+		return err
+	}
+
+	if dest.FailedAttempts < loginIPMaxFailedAttempts || dest.FirstAttemptAt == nil {
+		return nil
+	}
+
+	return &TooManyRequestsError{
+		msg:        fmt.Sprintf("Too many failed logins from %s", ipAddress),
+		RetryAfter: dest.FirstAttemptAt.Add(loginFailedAttemptsWindow).Sub(now),
+	}
+}
+
+func (usersService *UsersService) recordLoginAttempt(ctx context.Context, userId *uuid.UUID, email string, ipAddress string, succeeded bool) error {
+	loginAttempt := model.LoginAttempt{
+		UserID:    userId,
+		Email:     email,
+		IPAddress: ipAddress,
+		Succeeded: succeeded,
+	}
+
+	insertStmt := LoginAttempt.INSERT(LoginAttempt.UserID, LoginAttempt.Email, LoginAttempt.IPAddress, LoginAttempt.Succeeded).MODEL(loginAttempt)
+
+	_, err := insertStmt.ExecContext(ctx, usersService.db)
+
+	return err
+}
+
+func (usersService *UsersService) countFailedLogins(ctx context.Context, userId uuid.UUID, since time.Time) (*int, error) {
+	var dest struct {
+		FailedAttempts int
+	}
+
+	succeededLoginAttempt := LoginAttempt.AS("succeeded_login_attempt")
+
+	countStmt := SELECT(COUNT(STAR).AS("failed_attempts")).FROM(LoginAttempt).WHERE(LoginAttempt.UserID.EQ(UUID(userId)).AND(LoginAttempt.Succeeded.IS_FALSE()).AND(LoginAttempt.CreatedAt.GT(TimestampzT(since))).AND(NOT(EXISTS(succeededLoginAttempt.SELECT(succeededLoginAttempt.ID).WHERE(succeededLoginAttempt.UserID.EQ(UUID(userId)).AND(succeededLoginAttempt.Succeeded.IS_TRUE()).AND(succeededLoginAttempt.CreatedAt.GT(LoginAttempt.CreatedAt)))))))
+
+	if err := countStmt.QueryContext(ctx, usersService.db, &dest); err != nil {
+		return nil, err
+	}
+
+	return &dest.FailedAttempts, nil
+}
+
+func (usersService *UsersService) listRecentAccountLockouts(ctx context.Context, userId uuid.UUID, now time.Time) (*[]model.AccountLockout, error) {
+	lockouts := []model.AccountLockout{}
+
+	listStmt := SELECT(AccountLockout.AllColumns).FROM(AccountLockout).WHERE(AccountLockout.UserID.EQ(UUID(userId)).AND(AccountLockout.CreatedAt.GT(TimestampzT(now.Add(-accountLockoutBackoffWindow))))).ORDER_BY(AccountLockout.CreatedAt.DESC())
+
+	if err := listStmt.QueryContext(ctx, usersService.db, &lockouts); err != nil {
+		return nil, err
+	}
+
+	return &lockouts, nil
+}
+
+func (usersService *UsersService) lockAccount(ctx context.Context, userId uuid.UUID, failedAttempts int, ipAddress string, recentLockouts []model.AccountLockout, now time.Time) error {
+	lockoutDuration := accountLockoutDuration
+
+	// Lockouts ended by support don't count towards the backoff.
+	for _, lockout := range recentLockouts {
+		if lockout.UnlockedAt == nil && lockoutDuration < accountLockoutMaxDuration {
+			lockoutDuration *= 2
+		}
+	}
+
+	lockoutDuration = min(lockoutDuration, accountLockoutMaxDuration)
+
+	usersService.logger.WarnContext(ctx, "Locking user", "userId", userId, "failedAttempts", failedAttempts, "ipAddress", ipAddress, "lockoutDuration", lockoutDuration.String())
+
+	lockout := model.AccountLockout{
+		UserID:         &userId,
+		FailedAttempts: int32(failedAttempts),
+		IPAddress:      ipAddress,
+		LockedUntil:    now.Add(lockoutDuration),
+	}
+
+	insertStmt := AccountLockout.INSERT(AccountLockout.UserID, AccountLockout.FailedAttempts, AccountLockout.IPAddress, AccountLockout.LockedUntil).MODEL(lockout)
+
+	_, err := insertStmt.ExecContext(ctx, usersService.db)
+
+	return err
+}
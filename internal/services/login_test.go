@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLoginLocksAccount(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+	ipAddress := uniqueIPAddress(t)
+
+	for i := 0; i < loginMaxFailedAttempts; i++ {
+		var unauthenticatedError *UnauthenticatedError
+
+		if _, err := usersService.Login(ctx, user.Email, "wrong password", ipAddress); !errors.As(err, &unauthenticatedError) {
+			t.Fatalf("got error %v for failed login %d, expected an UnauthenticatedError", err, i+1)
+		}
+	}
+
+	var unauthenticatedError *UnauthenticatedError
+
+	if _, err := usersService.Login(ctx, user.Email, "wrong password", ipAddress); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v for a wrong password on a locked account, expected an UnauthenticatedError", err)
+	}
+
+	var accountLockedError *AccountLockedError
+
+	if _, err := usersService.Login(ctx, user.Email, testPassword, ipAddress); !errors.As(err, &accountLockedError) {
+		t.Fatalf("got error %v for the correct password on a locked account, expected an AccountLockedError", err)
+	}
+
+	if err := usersService.UnlockAccount(ctx, user.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := usersService.Login(ctx, user.Email, testPassword, ipAddress); err != nil {
+		t.Errorf("got error %v after unlocking the account", err)
+	}
+}
+
+func TestLoginUnknownEmail(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	var unauthenticatedError *UnauthenticatedError
+
+	if _, err := usersService.Login(context.Background(), uniqueName(t, "unknown-")+"@example.com", testPassword, uniqueIPAddress(t)); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v, expected an UnauthenticatedError", err)
+	}
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+type SMTPMailer struct {
+	host         string
+	port         int
+	username     string
+	password     string
+	from         string
+	envelopeFrom string
+}
+
+type WriterMailer struct {
+	from   string
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func NewSMTPMailer(host string, port int, username string, password string, from string) SMTPMailer {
+	envelopeFrom := from
+	if fromAddress, err := netmail.ParseAddress(from); err == nil {
+		envelopeFrom = fromAddress.Address
+	}
+
+	return SMTPMailer{
+		host:         host,
+		port:         port,
+		username:     username,
+		password:     password,
+		from:         from,
+		envelopeFrom: envelopeFrom,
+	}
+}
+
+func NewWriterMailer(writer io.Writer, from string) *WriterMailer {
+	return &WriterMailer{
+		from:   from,
+		writer: writer,
+	}
+}
+
+func (smtpMailer *SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(smtpMailer.host, strconv.Itoa(smtpMailer.port)))
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, smtpMailer.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: smtpMailer.host}); err != nil {
+			return err
+		}
+	}
+
+	if smtpMailer.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", smtpMailer.username, smtpMailer.password, smtpMailer.host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(smtpMailer.envelopeFrom); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(mail.To); err != nil {
+		return err
+	}
+
+	data, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(data, formatMail(smtpMailer.from, mail)); err != nil {
+		return err
+	}
+
+	if err = data.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (writerMailer *WriterMailer) Send(ctx context.Context, mail Mail) error {
+	writerMailer.mu.Lock()
+	defer writerMailer.mu.Unlock()
+
+	_, err := io.WriteString(writerMailer.writer, formatMail(writerMailer.from, mail)+"\r\n")
+
+	return err
+}
+
+func formatMail(from string, mail Mail) string {
+	var message strings.Builder
+
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	message.WriteString("\r\n")
+
+	return message.String()
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// beginTestMigration runs migrations in a new schema, in a transaction that is rolled back after the test.
+func beginTestMigration(t *testing.T, db *sql.DB, migrations ...string) *sql.Tx {
+	t.Helper()
+
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+
+	schema := uniqueName(t, "migration_test_")
+
+	if _, err = tx.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = tx.ExecContext(ctx, "SET LOCAL search_path TO "+schema+", public"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, migration := range migrations {
+		if _, err = tx.ExecContext(ctx, readTestMigration(t, migration)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return tx
+}
+
+func readTestMigration(t *testing.T, migration string) string {
+	t.Helper()
+
+	migrationSQL, err := os.ReadFile(filepath.Join("..", "..", "migrations", migration))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(migrationSQL)
+}
+
+func TestCaseInsensitiveUniquenessMigrationRenamesDuplicateUsernames(t *testing.T) {
+	db := newTestDB(t)
+
+	ctx := context.Background()
+
+	tx := beginTestMigration(t, db, "000001_initial_migration.up.sql")
+
+	insertUsersSQL := `INSERT INTO users (email, username, password_hash, created_at) VALUES
+		('alice@example.com', 'Alice', '', '2024-01-01'),
+		('alice2@example.com', 'alice', '', '2024-01-02'),
+		('alice3@example.com', 'ALICE', '', '2024-01-03'),
+		('alice4@example.com', 'alice_1', '', '2024-01-04'),
+		('bob@example.com', 'bob', '', '2024-01-05')`
+
+	if _, err := tx.ExecContext(ctx, insertUsersSQL); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, readTestMigration(t, "000016_add_users_case_insensitive_uniqueness.up.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := tx.QueryContext(ctx, "SELECT email, username FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	usernames := map[string]string{}
+
+	for rows.Next() {
+		var email, username string
+
+		if err = rows.Scan(&email, &username); err != nil {
+			t.Fatal(err)
+		}
+
+		usernames[email] = username
+	}
+
+	if err = rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUsernames := map[string]string{
+		"alice@example.com":  "Alice",
+		"alice2@example.com": "alice_2",
+		"alice3@example.com": "ALICE_3",
+		"alice4@example.com": "alice_1",
+		"bob@example.com":    "bob",
+	}
+
+	for email, expectedUsername := range expectedUsernames {
+		if usernames[email] != expectedUsername {
+			t.Errorf("got username %s for %s, expected %s", usernames[email], email, expectedUsername)
+		}
+	}
+}
+
+func TestCaseInsensitiveUniquenessMigrationFailsOnDuplicateEmails(t *testing.T) {
+	db := newTestDB(t)
+
+	ctx := context.Background()
+
+	tx := beginTestMigration(t, db, "000001_initial_migration.up.sql")
+
+	insertUsersSQL := `INSERT INTO users (email, username, password_hash, created_at) VALUES
+		('Carol@example.com', 'carol', '', '2024-01-01'),
+		('carol@example.com', 'carol2', '', '2024-01-02')`
+
+	if _, err := tx.ExecContext(ctx, insertUsersSQL); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := tx.ExecContext(ctx, readTestMigration(t, "000016_add_users_case_insensitive_uniqueness.up.sql"))
+	if err == nil {
+		t.Fatal("expected the migration to fail")
+	}
+
+	if !strings.Contains(err.Error(), "Carol@example.com, carol@example.com") {
+		t.Errorf("got error %v, expected it to list the duplicate emails", err)
+	}
+}
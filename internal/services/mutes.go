@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// MuteUser hides the articles and comments of the muted user from the muter, without the muted user knowing.
+func (profilesService *ProfilesService) MuteUser(ctx context.Context, muterId uuid.UUID, mutedId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Muting user", "muterId", muterId, "mutedId", mutedId)
+
+	if muterId == mutedId {
+		return &InvalidArgumentError{msg: "Users cannot mute themselves"}
+	}
+
+	muted, err := profilesService.usersService.GetUserById(ctx, mutedId)
+	if err != nil {
+		return err
+	}
+
+	muteStmt := Mute.INSERT(Mute.MuterID, Mute.MutedID).VALUES(UUID(muterId), UUID(muted.ID)).ON_CONFLICT(Mute.MuterID, Mute.MutedID).DO_NOTHING()
+
+	_, err = muteStmt.ExecContext(ctx, profilesService.db)
+
+	return err
+}
+
+func (profilesService *ProfilesService) UnmuteUser(ctx context.Context, muterId uuid.UUID, mutedId uuid.UUID) error {
+	profilesService.logger.InfoContext(ctx, "Unmuting user", "muterId", muterId, "mutedId", mutedId)
+
+	unmuteStmt := Mute.DELETE().WHERE(Mute.MuterID.EQ(UUID(muterId)).AND(Mute.MutedID.EQ(UUID(mutedId))))
+
+	_, err := unmuteStmt.ExecContext(ctx, profilesService.db)
+
+	return err
+}
+
+func (profilesService *ProfilesService) ListMutedProfiles(ctx context.Context, userId uuid.UUID) (*[]Profile, error) {
+	selectStmt := profilesService.selectProfiles(&userId).
+		FROM(Mute.INNER_JOIN(Users, Users.ID.EQ(Mute.MutedID))).
+		WHERE(Mute.MuterID.EQ(UUID(userId))).
+		ORDER_BY(Mute.CreatedAt.DESC(), Mute.ID.DESC())
+
+	return profilesService.queryProfiles(ctx, selectStmt)
+}
+
+func isMutedBy(authorId ColumnString, muterId uuid.UUID) BoolExpression {
+	return EXISTS(Mute.SELECT(Mute.ID).WHERE(Mute.MuterID.EQ(UUID(muterId)).AND(Mute.MutedID.EQ(authorId))))
+}
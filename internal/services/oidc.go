@@ -0,0 +1,574 @@
+package services
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+const oidcLoginStateValidFor = 10 * time.Minute
+
+type OIDCService struct {
+	config       OIDCServiceConfig
+	db           *sql.DB
+	httpClient   *http.Client
+	logger       *slog.Logger
+	usersService *UsersService
+	mu           sync.Mutex
+	// keys are fetched again when a token is signed with an unknown key, as the identity provider rotates them.
+	provider *oidcProvider
+	keys     map[string]interface{}
+}
+
+type OIDCServiceConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+type OIDCLogin struct {
+	URL       string
+	State     string
+	ExpiresAt time.Time
+}
+
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcJSONWebKey struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid"`
+	Use     string `json:"use"`
+	N       string `json:"n"`
+	E       string `json:"e"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y"`
+}
+
+type oidcTokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+type oidcIDTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	AuthorizedParty   string `json:"azp"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	PreferredUsername string `json:"preferred_username"`
+	jwt.RegisteredClaims
+}
+
+func NewOIDCService(db *sql.DB, usersService *UsersService, httpClient *http.Client, config OIDCServiceConfig, logger *slog.Logger) OIDCService {
+	return OIDCService{
+		config:       config,
+		db:           db,
+		httpClient:   httpClient,
+		logger:       logger,
+		usersService: usersService,
+	}
+}
+
+func (oidcService *OIDCService) StartLogin(ctx context.Context) (*OIDCLogin, error) {
+	provider, err := oidcService.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	codeVerifier, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	deleteExpiredStmt := OidcLoginState.DELETE().WHERE(OidcLoginState.ExpiresAt.LT(TimestampzT(now)))
+
+	if _, err = deleteExpiredStmt.ExecContext(ctx, oidcService.db); err != nil {
+		return nil, err
+	}
+
+	oidcLoginState := model.OidcLoginState{
+		StateHash:    hashToken(*state),
+		Nonce:        *nonce,
+		CodeVerifier: *codeVerifier,
+		ExpiresAt:    now.Add(oidcLoginStateValidFor),
+	}
+
+	insertStmt := OidcLoginState.INSERT(OidcLoginState.StateHash, OidcLoginState.Nonce, OidcLoginState.CodeVerifier, OidcLoginState.ExpiresAt).MODEL(oidcLoginState)
+
+	if _, err = insertStmt.ExecContext(ctx, oidcService.db); err != nil {
+		return nil, err
+	}
+
+	codeChallenge := sha256.Sum256([]byte(*codeVerifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", oidcService.config.ClientID)
+	query.Set("redirect_uri", oidcService.config.RedirectURL)
+	query.Set("scope", "openid email profile")
+	query.Set("state", *state)
+	query.Set("nonce", *nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(codeChallenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	authorizationURL, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	// The authorization endpoint may already have query parameters, which must be kept.
+	authorizationQuery := authorizationURL.Query()
+	for key, values := range query {
+		authorizationQuery[key] = values
+	}
+	authorizationURL.RawQuery = authorizationQuery.Encode()
+
+	return &OIDCLogin{
+		URL:       authorizationURL.String(),
+		State:     *state,
+		ExpiresAt: oidcLoginState.ExpiresAt,
+	}, nil
+}
+
+// CompleteLogin links new identities to the user with the same email, or creates one, if the email is verified.
+func (oidcService *OIDCService) CompleteLogin(ctx context.Context, state string, code string) (*model.Users, error) {
+	var oidcLoginState model.OidcLoginState
+
+	deleteStateStmt := OidcLoginState.DELETE().WHERE(OidcLoginState.StateHash.EQ(String(hashToken(state)))).RETURNING(OidcLoginState.AllColumns)
+
+	err := deleteStateStmt.QueryContext(ctx, oidcService.db, &oidcLoginState)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &UnauthenticatedError{msg: "Invalid OIDC login state"}
+		}
+		return nil, err
+	}
+
+	if time.Now().After(oidcLoginState.ExpiresAt) {
+		return nil, &UnauthenticatedError{msg: "OIDC login has expired"}
+	}
+
+	idToken, err := oidcService.exchangeCode(ctx, code, oidcLoginState.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := oidcService.verifyIDToken(ctx, *idToken, oidcLoginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcService.getOrCreateUser(ctx, *claims)
+}
+
+func (oidcService *OIDCService) exchangeCode(ctx context.Context, code string, codeVerifier string) (*string, error) {
+	provider, err := oidcService.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", oidcService.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	// client_secret_basic requires the credentials to be form encoded before being base64 encoded (RFC 6749, section 2.3.1).
+	request.SetBasicAuth(url.QueryEscape(oidcService.config.ClientID), url.QueryEscape(oidcService.config.ClientSecret))
+
+	var tokenResponse oidcTokenResponse
+
+	if err = oidcService.doJSON(request, &tokenResponse); err != nil {
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("OIDC code exchange failed: %s", err.Error())}
+	}
+
+	if tokenResponse.IDToken == "" {
+		return nil, &UnauthenticatedError{msg: "OIDC token response has no ID token"}
+	}
+
+	return &tokenResponse.IDToken, nil
+}
+
+func (oidcService *OIDCService) verifyIDToken(ctx context.Context, idToken string, nonce string) (*oidcIDTokenClaims, error) {
+	var claims oidcIDTokenClaims
+
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		keyId, _ := token.Header["kid"].(string)
+		return oidcService.getKey(ctx, keyId)
+	},
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(oidcService.config.Issuer),
+		jwt.WithAudience(oidcService.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("Invalid OIDC ID token: %s", err.Error())}
+	}
+
+	if claims.Nonce != nonce {
+		return nil, &UnauthenticatedError{msg: "Invalid OIDC ID token nonce"}
+	}
+
+	if claims.AuthorizedParty != "" && claims.AuthorizedParty != oidcService.config.ClientID {
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("OIDC ID token was issued to %s", claims.AuthorizedParty)}
+	}
+
+	if claims.Subject == "" {
+		return nil, &UnauthenticatedError{msg: "OIDC ID token has no subject"}
+	}
+
+	return &claims, nil
+}
+
+func (oidcService *OIDCService) getOrCreateUser(ctx context.Context, claims oidcIDTokenClaims) (*model.Users, error) {
+	user, err := oidcService.getUserByIdentity(ctx, claims)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, qrm.ErrNoRows) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("OIDC user %s has no verified email", claims.Subject)}
+	}
+
+	user, err = oidcService.usersService.GetUserByEmail(ctx, claims.Email)
+	if err != nil {
+		var notFoundError *NotFoundError
+		if !errors.As(err, &notFoundError) {
+			return nil, err
+		}
+
+		user, err = oidcService.createUser(ctx, claims)
+		if err != nil {
+			// A concurrent first login of the same identity may have created the user first.
+			existingUser, getUserErr := oidcService.usersService.GetUserByEmail(ctx, claims.Email)
+			if getUserErr != nil {
+				return nil, err
+			}
+
+			user = existingUser
+		}
+	}
+
+	oidcService.logger.InfoContext(ctx, "Linking OIDC identity", "userId", user.ID, "issuer", claims.Issuer, "subject", claims.Subject)
+
+	tx, err := oidcService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userIdentity := model.UserIdentity{
+		UserID:  &user.ID,
+		Issuer:  claims.Issuer,
+		Subject: claims.Subject,
+	}
+
+	insertIdentityStmt := UserIdentity.INSERT(UserIdentity.UserID, UserIdentity.Issuer, UserIdentity.Subject).MODEL(userIdentity).ON_CONFLICT(UserIdentity.Issuer, UserIdentity.Subject).DO_NOTHING()
+
+	sqlResult, err := insertIdentityStmt.ExecContext(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected == 0 {
+		oidcService.logger.InfoContext(ctx, "OIDC identity linked by a concurrent login", "issuer", claims.Issuer, "subject", claims.Subject)
+
+		return oidcService.getUserByIdentity(ctx, claims)
+	}
+
+	// The identity provider verified the email, so users don't have to verify it again.
+	if user.EmailVerifiedAt == nil {
+		now := time.Now().UTC()
+
+		verifyEmailStmt := Users.UPDATE(Users.EmailVerifiedAt).SET(TimestampzT(now)).WHERE(Users.ID.EQ(UUID(user.ID)))
+
+		if _, err = verifyEmailStmt.ExecContext(ctx, tx); err != nil {
+			return nil, err
+		}
+
+		user.EmailVerifiedAt = &now
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (oidcService *OIDCService) getUserByIdentity(ctx context.Context, claims oidcIDTokenClaims) (*model.Users, error) {
+	var userIdentity model.UserIdentity
+
+	getIdentityStmt := SELECT(UserIdentity.AllColumns).FROM(UserIdentity).WHERE(UserIdentity.Issuer.EQ(String(claims.Issuer)).AND(UserIdentity.Subject.EQ(String(claims.Subject))))
+
+	if err := getIdentityStmt.QueryContext(ctx, oidcService.db, &userIdentity); err != nil {
+		return nil, err
+	}
+
+	return oidcService.usersService.GetUserById(ctx, *userIdentity.UserID)
+}
+
+// createUser gives the user a random password, which can be changed by resetting it.
+func (oidcService *OIDCService) createUser(ctx context.Context, claims oidcIDTokenClaims) (*model.Users, error) {
+	username, err := oidcService.makeUsername(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return oidcService.usersService.RegisterUser(ctx, NewRegisterUser(claims.Email, *username, *password))
+}
+
+func (oidcService *OIDCService) makeUsername(ctx context.Context, claims oidcIDTokenClaims) (*string, error) {
+	baseUsername := claims.PreferredUsername
+	if baseUsername == "" {
+		baseUsername, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	baseUsername = strings.Trim(usernameInvalidCharacters.ReplaceAllString(baseUsername, "-"), "-")
+	if len(baseUsername) < minUsernameLength {
+		baseUsername = "user"
+	}
+
+	// Leave room for the number.
+	if len(baseUsername) > maxUsernameLength-4 {
+		baseUsername = baseUsername[:maxUsernameLength-4]
+	}
+
+	username := baseUsername
+
+	for i := 2; ; i++ {
+		err := oidcService.usersService.validateUsername(ctx, username, nil)
+		if err == nil {
+			return &username, nil
+		}
+
+		var alreadyExistsError *AlreadyExistsError
+		var invalidArgumentError *InvalidArgumentError
+		if !errors.As(err, &alreadyExistsError) && !errors.As(err, &invalidArgumentError) {
+			return nil, err
+		}
+
+		username = fmt.Sprintf("%s%d", baseUsername, i)
+	}
+}
+
+func (oidcService *OIDCService) getProvider(ctx context.Context) (*oidcProvider, error) {
+	oidcService.mu.Lock()
+	defer oidcService.mu.Unlock()
+
+	if oidcService.provider != nil {
+		return oidcService.provider, nil
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(oidcService.config.Issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var provider oidcProvider
+
+	if err = oidcService.doJSON(request, &provider); err != nil {
+		return nil, fmt.Errorf("OIDC discovery failed: %w", err)
+	}
+
+	if provider.Issuer != oidcService.config.Issuer {
+		return nil, fmt.Errorf("OIDC discovery returned issuer %s, expected %s", provider.Issuer, oidcService.config.Issuer)
+	}
+
+	oidcService.provider = &provider
+
+	return oidcService.provider, nil
+}
+
+func (oidcService *OIDCService) getKey(ctx context.Context, keyId string) (interface{}, error) {
+	oidcService.mu.Lock()
+	key, ok := oidcService.keys[keyId]
+	oidcService.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	keys, err := oidcService.fetchKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcService.mu.Lock()
+	oidcService.keys = keys
+	oidcService.mu.Unlock()
+
+	key, ok = keys[keyId]
+	if !ok {
+		// Tokens may omit the key ID when the identity provider only has one key.
+		if keyId == "" && len(keys) == 1 {
+			for _, key := range keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key %s", keyId)
+	}
+
+	return key, nil
+}
+
+func (oidcService *OIDCService) fetchKeys(ctx context.Context) (map[string]interface{}, error) {
+	provider, err := oidcService.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var jsonWebKeySet struct {
+		Keys []oidcJSONWebKey `json:"keys"`
+	}
+
+	if err = oidcService.doJSON(request, &jsonWebKeySet); err != nil {
+		return nil, fmt.Errorf("OIDC JWKS request failed: %w", err)
+	}
+
+	keys := map[string]interface{}{}
+
+	for _, jsonWebKey := range jsonWebKeySet.Keys {
+		if jsonWebKey.Use != "" && jsonWebKey.Use != "sig" {
+			continue
+		}
+
+		key, err := parseOIDCJSONWebKey(jsonWebKey)
+		if err != nil {
+			oidcService.logger.WarnContext(ctx, "Skipping OIDC key", "keyId", jsonWebKey.KeyID, "error", err.Error())
+			continue
+		}
+
+		if key != nil {
+			keys[jsonWebKey.KeyID] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (oidcService *OIDCService) doJSON(request *http.Request, dest interface{}) error {
+	response, err := oidcService.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s %s returned %d: %s", request.Method, request.URL, response.StatusCode, body)
+	}
+
+	return json.Unmarshal(body, dest)
+}
+
+// parseOIDCJSONWebKey returns the public key of an RSA or P-256 key, or nil for other key types.
+func parseOIDCJSONWebKey(jsonWebKey oidcJSONWebKey) (interface{}, error) {
+	switch jsonWebKey.KeyType {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(jsonWebKey.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := base64.RawURLEncoding.DecodeString(jsonWebKey.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		if jsonWebKey.Curve != "P-256" {
+			return nil, nil
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(jsonWebKey.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(jsonWebKey.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	default:
+		return nil, nil
+	}
+}
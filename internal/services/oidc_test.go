@@ -0,0 +1,460 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+)
+
+const (
+	testOIDCClientID     = "test-client"
+	testOIDCClientSecret = "test-secret"
+	testOIDCRedirectURL  = "http://localhost:8080/auth/oidc/callback"
+	testOIDCKeyID        = "test-key"
+)
+
+type testOIDCProvider struct {
+	server         *httptest.Server
+	key            *rsa.PrivateKey
+	mu             sync.Mutex
+	authorizations map[string]testOIDCAuthorization
+}
+
+type testOIDCAuthorization struct {
+	codeChallenge string
+	claims        oidcIDTokenClaims
+}
+
+func newTestOIDCProvider(t *testing.T) *testOIDCProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &testOIDCProvider{
+		key:            key,
+		authorizations: map[string]testOIDCAuthorization{},
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, oidcProvider{
+			Issuer:                provider.server.URL,
+			AuthorizationEndpoint: provider.server.URL + "/authorize?prompt=login",
+			TokenEndpoint:         provider.server.URL + "/token",
+			JWKSURI:               provider.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string][]oidcJSONWebKey{
+			"keys": {
+				{
+					KeyType: "RSA",
+					KeyID:   testOIDCKeyID,
+					Use:     "sig",
+					N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	mux.HandleFunc("POST /token", provider.handleToken)
+
+	provider.server = httptest.NewServer(mux)
+	t.Cleanup(provider.server.Close)
+
+	return provider
+}
+
+func (provider *testOIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok || clientId != testOIDCClientID || clientSecret != testOIDCClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+
+	if r.PostFormValue("grant_type") != "authorization_code" || r.PostFormValue("redirect_uri") != testOIDCRedirectURL {
+		http.Error(w, `{"error":"invalid_request"}`, http.StatusBadRequest)
+		return
+	}
+
+	provider.mu.Lock()
+	authorization, ok := provider.authorizations[r.PostFormValue("code")]
+	delete(provider.authorizations, r.PostFormValue("code"))
+	provider.mu.Unlock()
+
+	codeChallenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+
+	if !ok || base64.RawURLEncoding.EncodeToString(codeChallenge[:]) != authorization.codeChallenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	writeTestJSON(w, oidcTokenResponse{IDToken: provider.signIDToken(authorization.claims)})
+}
+
+func (provider *testOIDCProvider) signIDToken(claims oidcIDTokenClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testOIDCKeyID
+
+	idToken, err := token.SignedString(provider.key)
+	if err != nil {
+		panic(err)
+	}
+
+	return idToken
+}
+
+// authorize stands in for the identity provider's login page.
+func (provider *testOIDCProvider) authorize(t *testing.T, authorizationURL string, claims oidcIDTokenClaims) (string, string) {
+	t.Helper()
+
+	parsedURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := parsedURL.Query()
+
+	if query.Get("prompt") != "login" {
+		t.Errorf("authorization URL lost the query parameters of the endpoint: %s", authorizationURL)
+	}
+
+	if query.Get("response_type") != "code" || query.Get("client_id") != testOIDCClientID || query.Get("redirect_uri") != testOIDCRedirectURL {
+		t.Fatalf("unexpected authorization request %s", authorizationURL)
+	}
+
+	if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		t.Fatalf("authorization request %s doesn't use PKCE", authorizationURL)
+	}
+
+	claims.Issuer = provider.server.URL
+	claims.Audience = jwt.ClaimStrings{testOIDCClientID}
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+	claims.Nonce = query.Get("nonce")
+
+	code := uniqueName(t, "code-")
+
+	provider.mu.Lock()
+	provider.authorizations[code] = testOIDCAuthorization{
+		codeChallenge: query.Get("code_challenge"),
+		claims:        claims,
+	}
+	provider.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func newTestOIDCService(provider *testOIDCProvider, usersService *UsersService) *OIDCService {
+	oidcService := NewOIDCService(usersService.db, usersService, provider.server.Client(), OIDCServiceConfig{
+		Issuer:       provider.server.URL,
+		ClientID:     testOIDCClientID,
+		ClientSecret: testOIDCClientSecret,
+		RedirectURL:  testOIDCRedirectURL,
+	}, newTestLogger())
+
+	return &oidcService
+}
+
+func writeTestJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+func TestOIDCServiceDiscovery(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	oidcService := newTestOIDCService(provider, &UsersService{})
+
+	discovered, err := oidcService.getProvider(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if discovered.TokenEndpoint != provider.server.URL+"/token" || discovered.JWKSURI != provider.server.URL+"/jwks" {
+		t.Errorf("unexpected provider metadata %+v", discovered)
+	}
+
+	otherIssuerService := newTestOIDCService(provider, &UsersService{})
+	otherIssuerService.config.Issuer = provider.server.URL + "/"
+
+	if _, err = otherIssuerService.getProvider(context.Background()); err == nil {
+		t.Error("expected discovery to fail when the issuer doesn't match")
+	}
+}
+
+func TestOIDCServiceVerifyIDToken(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	oidcService := newTestOIDCService(provider, &UsersService{})
+
+	claims := oidcIDTokenClaims{
+		Nonce: "nonce",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    provider.server.URL,
+			Subject:   "subject",
+			Audience:  jwt.ClaimStrings{testOIDCClientID},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+
+	verified, err := oidcService.verifyIDToken(context.Background(), provider.signIDToken(claims), "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verified.Subject != "subject" {
+		t.Errorf("got subject %s, expected subject", verified.Subject)
+	}
+
+	otherAudienceClaims := claims
+	otherAudienceClaims.Audience = jwt.ClaimStrings{"other-client"}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherKeyToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	otherKeyToken.Header["kid"] = testOIDCKeyID
+
+	otherKeyIDToken, err := otherKeyToken.SignedString(otherKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		idToken string
+		nonce   string
+	}{
+		{name: "wrong nonce", idToken: provider.signIDToken(claims), nonce: "other-nonce"},
+		{name: "wrong audience", idToken: provider.signIDToken(otherAudienceClaims), nonce: "nonce"},
+		{name: "wrong key", idToken: otherKeyIDToken, nonce: "nonce"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := oidcService.verifyIDToken(context.Background(), testCase.idToken, testCase.nonce)
+
+			var unauthenticatedError *UnauthenticatedError
+			if !errors.As(err, &unauthenticatedError) {
+				t.Errorf("got error %v, expected an UnauthenticatedError", err)
+			}
+		})
+	}
+}
+
+func TestOIDCServiceExchangeCode(t *testing.T) {
+	provider := newTestOIDCProvider(t)
+	oidcService := newTestOIDCService(provider, &UsersService{})
+
+	codeVerifier := "code-verifier"
+	codeChallenge := sha256.Sum256([]byte(codeVerifier))
+
+	provider.authorizations["code"] = testOIDCAuthorization{
+		codeChallenge: base64.RawURLEncoding.EncodeToString(codeChallenge[:]),
+	}
+	provider.authorizations["other-code"] = provider.authorizations["code"]
+
+	var unauthenticatedError *UnauthenticatedError
+
+	if _, err := oidcService.exchangeCode(context.Background(), "other-code", "other-code-verifier"); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v for the wrong code verifier, expected an UnauthenticatedError", err)
+	}
+
+	if _, err := oidcService.exchangeCode(context.Background(), "code", codeVerifier); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := oidcService.exchangeCode(context.Background(), "code", codeVerifier); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v for a used code, expected an UnauthenticatedError", err)
+	}
+}
+
+func TestOIDCServiceCompleteLogin(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	provider := newTestOIDCProvider(t)
+	oidcService := newTestOIDCService(provider, usersService)
+
+	ctx := context.Background()
+
+	login := func(t *testing.T, claims oidcIDTokenClaims) (*OIDCLogin, string) {
+		t.Helper()
+
+		oidcLogin, err := oidcService.StartLogin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		code, state := provider.authorize(t, oidcLogin.URL, claims)
+
+		if state != oidcLogin.State {
+			t.Fatalf("got state %s, expected %s", state, oidcLogin.State)
+		}
+
+		return oidcLogin, code
+	}
+
+	t.Run("creates a user and signs them in again", func(t *testing.T) {
+		email := uniqueName(t, "oidc-") + "@example.com"
+
+		claims := oidcIDTokenClaims{
+			Email:         email,
+			EmailVerified: true,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: uniqueName(t, "subject-"),
+			},
+		}
+
+		oidcLogin, code := login(t, claims)
+
+		user, err := oidcService.CompleteLogin(ctx, oidcLogin.State, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user.Email != email || user.EmailVerifiedAt == nil {
+			t.Errorf("got user %s with email verified at %v, expected a verified user with email %s", user.Email, user.EmailVerifiedAt, email)
+		}
+
+		claims.Email = uniqueName(t, "oidc-") + "@example.com"
+
+		oidcLogin, code = login(t, claims)
+
+		userAgain, err := oidcService.CompleteLogin(ctx, oidcLogin.State, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if userAgain.ID != user.ID {
+			t.Errorf("got user %s, expected %s", userAgain.ID, user.ID)
+		}
+	})
+
+	t.Run("concurrent first logins get the same user", func(t *testing.T) {
+		claims := oidcIDTokenClaims{
+			Email:         uniqueName(t, "oidc-") + "@example.com",
+			EmailVerified: true,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: uniqueName(t, "subject-"),
+			},
+		}
+
+		const loginsCount = 4
+
+		var wg sync.WaitGroup
+
+		users := make([]*model.Users, loginsCount)
+		errs := make([]error, loginsCount)
+
+		for i := 0; i < loginsCount; i++ {
+			oidcLogin, code := login(t, claims)
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				users[i], errs[i] = oidcService.CompleteLogin(ctx, oidcLogin.State, code)
+			}(i)
+		}
+
+		wg.Wait()
+
+		for i := 0; i < loginsCount; i++ {
+			if errs[i] != nil {
+				t.Fatal(errs[i])
+			}
+
+			if users[i].ID != users[0].ID {
+				t.Errorf("got user %s, expected %s", users[i].ID, users[0].ID)
+			}
+		}
+	})
+
+	t.Run("links an existing user by email", func(t *testing.T) {
+		existingUser := createTestUser(t, usersService)
+
+		oidcLogin, code := login(t, oidcIDTokenClaims{
+			Email:         existingUser.Email,
+			EmailVerified: true,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: uniqueName(t, "subject-"),
+			},
+		})
+
+		user, err := oidcService.CompleteLogin(ctx, oidcLogin.State, code)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if user.ID != existingUser.ID {
+			t.Errorf("got user %s, expected %s", user.ID, existingUser.ID)
+		}
+	})
+
+	t.Run("rejects an unverified email", func(t *testing.T) {
+		existingUser := createTestUser(t, usersService)
+
+		oidcLogin, code := login(t, oidcIDTokenClaims{
+			Email:         existingUser.Email,
+			EmailVerified: false,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: uniqueName(t, "subject-"),
+			},
+		})
+
+		_, err := oidcService.CompleteLogin(ctx, oidcLogin.State, code)
+
+		var unauthenticatedError *UnauthenticatedError
+		if !errors.As(err, &unauthenticatedError) {
+			t.Errorf("got error %v, expected an UnauthenticatedError", err)
+		}
+	})
+
+	t.Run("rejects an unknown or used state", func(t *testing.T) {
+		claims := oidcIDTokenClaims{
+			Email:         uniqueName(t, "oidc-") + "@example.com",
+			EmailVerified: true,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject: uniqueName(t, "subject-"),
+			},
+		}
+
+		oidcLogin, code := login(t, claims)
+
+		var unauthenticatedError *UnauthenticatedError
+
+		if _, err := oidcService.CompleteLogin(ctx, uniqueName(t, "state-"), code); !errors.As(err, &unauthenticatedError) {
+			t.Errorf("got error %v for an unknown state, expected an UnauthenticatedError", err)
+		}
+
+		if _, err := oidcService.CompleteLogin(ctx, oidcLogin.State, code); err != nil {
+			t.Fatal(err)
+		}
+
+		_, code = login(t, claims)
+
+		if _, err := oidcService.CompleteLogin(ctx, oidcLogin.State, code); !errors.As(err, &unauthenticatedError) {
+			t.Errorf("got error %v for a used state, expected an UnauthenticatedError", err)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, hash string) (ok bool, needsRehash bool, err error)
+}
+
+type Argon2idParams struct {
+	// Memory is in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultArgon2idParams are the minimum parameters recommended by OWASP.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+}
+
+const (
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+	// bcryptMaxPasswordLength is the length bcrypt hashes were limited to, as longer passwords were truncated.
+	bcryptMaxPasswordLength = 72
+)
+
+var argon2idEncoding = base64.RawStdEncoding
+
+// Argon2idPasswordHasher also verifies the bcrypt hashes created by pgcrypto's crypt, which need to be rehashed.
+type Argon2idPasswordHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idPasswordHasher(params Argon2idParams) Argon2idPasswordHasher {
+	return Argon2idPasswordHasher{
+		params: params,
+	}
+}
+
+func (argon2idPasswordHasher *Argon2idPasswordHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	params := argon2idPasswordHasher.params
+
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.Memory, params.Iterations, params.Parallelism, argon2idEncoding.EncodeToString(salt), argon2idEncoding.EncodeToString(key)), nil
+}
+
+func (argon2idPasswordHasher *Argon2idPasswordHasher) Verify(password string, hash string) (bool, bool, error) {
+	if isBcryptHash(hash) {
+		if len(password) > bcryptMaxPasswordLength {
+			return false, false, nil
+		}
+
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, false, nil
+			}
+			return false, false, err
+		}
+
+		return true, true, nil
+	}
+
+	params, salt, key, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false, false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+
+	if subtle.ConstantTimeCompare(key, otherKey) != 1 {
+		return false, false, nil
+	}
+
+	needsRehash := *params != argon2idPasswordHasher.params || len(key) != argon2idKeyLength
+
+	return true, needsRehash, nil
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func parseArgon2idHash(hash string) (*Argon2idParams, []byte, []byte, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, errors.New("unsupported password hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	var params Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+
+	salt, err := argon2idEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+
+	key, err := argon2idEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id key: %w", err)
+	}
+
+	return &params, salt, key, nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idPasswordHasher(t *testing.T) {
+	passwordHasher := NewArgon2idPasswordHasher(testArgon2idParams)
+
+	hash, err := passwordHasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("got hash %s, expected an Argon2id hash with the configured parameters", hash)
+	}
+
+	otherHash, err := passwordHasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if otherHash == hash {
+		t.Error("expected hashes of the same password to have different salts")
+	}
+
+	longPassword := strings.Repeat("a", 1024)
+
+	longPasswordHash, err := passwordHasher.Hash(longPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name            string
+		password        string
+		hash            string
+		wantOk          bool
+		wantNeedsRehash bool
+	}{
+		{name: "correct password", password: testPassword, hash: hash, wantOk: true},
+		{name: "wrong password", password: "wrong password", hash: hash},
+		{name: "long password", password: longPassword, hash: longPasswordHash, wantOk: true},
+		{name: "long password with another last byte", password: longPassword[:1023] + "b", hash: longPasswordHash},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ok, needsRehash, err := passwordHasher.Verify(testCase.password, testCase.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok != testCase.wantOk || needsRehash != testCase.wantNeedsRehash {
+				t.Errorf("got ok %t and needs rehash %t, expected %t and %t", ok, needsRehash, testCase.wantOk, testCase.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestArgon2idPasswordHasherNeedsRehash(t *testing.T) {
+	passwordHasher := NewArgon2idPasswordHasher(testArgon2idParams)
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherParamsPasswordHasher := NewArgon2idPasswordHasher(Argon2idParams{Memory: 128, Iterations: 1, Parallelism: 1})
+
+	otherParamsHash, err := otherParamsPasswordHasher.Hash(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name            string
+		password        string
+		hash            string
+		wantOk          bool
+		wantNeedsRehash bool
+	}{
+		{name: "bcrypt", password: testPassword, hash: string(bcryptHash), wantOk: true, wantNeedsRehash: true},
+		{name: "bcrypt wrong password", password: "wrong password", hash: string(bcryptHash)},
+		{name: "bcrypt password longer than 72 bytes", password: testPassword + strings.Repeat("a", 72), hash: string(bcryptHash)},
+		{name: "other parameters", password: testPassword, hash: otherParamsHash, wantOk: true, wantNeedsRehash: true},
+		{name: "other parameters wrong password", password: "wrong password", hash: otherParamsHash},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ok, needsRehash, err := passwordHasher.Verify(testCase.password, testCase.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if ok != testCase.wantOk || needsRehash != testCase.wantNeedsRehash {
+				t.Errorf("got ok %t and needs rehash %t, expected %t and %t", ok, needsRehash, testCase.wantOk, testCase.wantNeedsRehash)
+			}
+		})
+	}
+}
+
+func TestArgon2idPasswordHasherInvalidHash(t *testing.T) {
+	passwordHasher := NewArgon2idPasswordHasher(testArgon2idParams)
+
+	for _, hash := range []string{"", "plain", "$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5", "$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5", "$argon2id$v=19$m=64$c2FsdA$a2V5"} {
+		if _, _, err := passwordHasher.Verify(testPassword, hash); err == nil {
+			t.Errorf("expected an error for hash %q", hash)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// RequestPasswordReset ignores unknown emails, so it can't be used to find out which emails are registered.
+func (usersService *UsersService) RequestPasswordReset(ctx context.Context, email string) error {
+	usersService.logger.InfoContext(ctx, "Requesting password reset", "email", email)
+
+	user, err := usersService.GetUserByEmail(ctx, email)
+	if err != nil {
+		if _, ok := err.(*NotFoundError); ok {
+			usersService.logger.InfoContext(ctx, "Password reset requested for unknown email", "email", email)
+			return nil
+		}
+		return err
+	}
+
+	return usersService.sendPasswordReset(ctx, *user, "Someone asked to reset the password of your account. If it wasn't you, you can ignore this email.")
+}
+
+// sendPasswordReset replaces the links sent before. intro explains why the email was sent.
+func (usersService *UsersService) sendPasswordReset(ctx context.Context, user model.Users, intro string) error {
+	token, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	deletePendingTokensStmt := PasswordResetToken.DELETE().WHERE(PasswordResetToken.UserID.EQ(UUID(user.ID)).AND(PasswordResetToken.UsedAt.IS_NULL()))
+
+	if _, err = deletePendingTokensStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	passwordResetToken := model.PasswordResetToken{
+		UserID:    &user.ID,
+		TokenHash: hashToken(*token),
+		ExpiresAt: time.Now().UTC().Add(time.Second * time.Duration(usersService.config.PasswordResetTokenValidForSeconds)),
+	}
+
+	insertTokenStmt := PasswordResetToken.INSERT(PasswordResetToken.UserID, PasswordResetToken.TokenHash, PasswordResetToken.ExpiresAt).MODEL(passwordResetToken)
+
+	if _, err = insertTokenStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	resetUrl := fmt.Sprintf("%s/reset-password?token=%s", usersService.config.AppURL, url.QueryEscape(*token))
+
+	return usersService.mailer.Send(ctx, Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\n%s To choose a new password, open the link below:\n\n%s\n\nThe link expires at %s.\n",
+			user.Username, intro, resetUrl, passwordResetToken.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+func (usersService *UsersService) ResetPassword(ctx context.Context, token string, password string) error {
+	passwordHash, err := usersService.hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var passwordResetToken model.PasswordResetToken
+
+	getTokenStmt := SELECT(PasswordResetToken.AllColumns).FROM(PasswordResetToken).WHERE(PasswordResetToken.TokenHash.EQ(String(hashToken(token)))).FOR(UPDATE())
+
+	err = getTokenStmt.QueryContext(ctx, tx, &passwordResetToken)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return &InvalidArgumentError{msg: "Invalid password reset token"}
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+
+	if passwordResetToken.UsedAt != nil || now.After(passwordResetToken.ExpiresAt) {
+		return &InvalidArgumentError{msg: "Password reset token is expired or has already been used"}
+	}
+
+	usersService.logger.InfoContext(ctx, "Resetting password", "userId", passwordResetToken.UserID)
+
+	markTokenUsedStmt := PasswordResetToken.UPDATE(PasswordResetToken.UsedAt).SET(TimestampzT(now)).WHERE(PasswordResetToken.ID.EQ(UUID(passwordResetToken.ID)))
+
+	if _, err = markTokenUsedStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	updatePasswordStmt := Users.UPDATE(Users.PasswordHash, Users.UpdatedAt).SET(String(*passwordHash), TimestampzT(now)).WHERE(Users.ID.EQ(UUID(passwordResetToken.UserID)))
+
+	if _, err = updatePasswordStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = usersService.revokeAllSessions(ctx, tx, *passwordResetToken.UserID); err != nil {
+		return err
+	}
+
+	if err = usersService.revokeAllPersonalAccessTokens(ctx, tx, *passwordResetToken.UserID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
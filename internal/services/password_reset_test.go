@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+var passwordResetTokenRegexp = regexp.MustCompile(`/reset-password\?token=(\S+)`)
+
+func requestTestPasswordReset(t *testing.T, usersService *UsersService, mail *bytes.Buffer, email string) string {
+	t.Helper()
+
+	mail.Reset()
+
+	if err := usersService.RequestPasswordReset(context.Background(), email); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(mail.String(), "To: "+email+"\r\n") {
+		t.Fatalf("no password reset mail sent to %s in %q", email, mail.String())
+	}
+
+	match := passwordResetTokenRegexp.FindStringSubmatch(mail.String())
+	if match == nil {
+		t.Fatalf("no password reset link in %q", mail.String())
+	}
+
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+func checkTestPassword(t *testing.T, usersService *UsersService, username string, password string) bool {
+	t.Helper()
+
+	user, err := usersService.GetUserByUsername(context.Background(), username)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isCorrectPassword, err := usersService.CheckPassword(context.Background(), user.ID, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return *isCorrectPassword
+}
+
+func TestResetPasswordIsSingleUse(t *testing.T) {
+	db := newTestDB(t)
+
+	var mail bytes.Buffer
+
+	usersService := newTestUsersServiceWithMailer(t, db, NewWriterMailer(&mail, "test@example.com"))
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	sessionToken, err := usersService.GetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, personalAccessToken, err := usersService.CreatePersonalAccessToken(ctx, user.ID, NewCreatePersonalAccessToken("ci", []string{ScopeArticlesWrite}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := requestTestPasswordReset(t, usersService, &mail, user.Email)
+
+	newPassword := "a brand new password"
+
+	if err = usersService.ResetPassword(ctx, token, newPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	if !checkTestPassword(t, usersService, user.Username, newPassword) {
+		t.Error("expected the password to be reset")
+	}
+
+	var invalidArgumentError *InvalidArgumentError
+
+	if err = usersService.ResetPassword(ctx, token, "yet another password"); !errors.As(err, &invalidArgumentError) {
+		t.Errorf("got error %v reusing the token, expected an InvalidArgumentError", err)
+	}
+
+	if !checkTestPassword(t, usersService, user.Username, newPassword) {
+		t.Error("expected the reused token not to change the password")
+	}
+
+	if _, _, err = usersService.RefreshSession(ctx, sessionToken.RefreshToken); err == nil {
+		t.Error("expected the sessions to be revoked")
+	}
+
+	if _, _, err = usersService.GetUserByPersonalAccessToken(ctx, *personalAccessToken); err == nil {
+		t.Error("expected the personal access tokens to be revoked")
+	}
+}
+
+func TestResetPasswordTokenExpires(t *testing.T) {
+	db := newTestDB(t)
+
+	var mail bytes.Buffer
+
+	usersService := newTestUsersServiceWithMailer(t, db, NewWriterMailer(&mail, "test@example.com"))
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	token := requestTestPasswordReset(t, usersService, &mail, user.Email)
+
+	expireTokenStmt := PasswordResetToken.UPDATE(PasswordResetToken.ExpiresAt).SET(TimestampzT(time.Now().UTC().Add(-time.Minute))).WHERE(PasswordResetToken.TokenHash.EQ(String(hashToken(token))))
+
+	if _, err := expireTokenStmt.ExecContext(ctx, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var invalidArgumentError *InvalidArgumentError
+
+	if err := usersService.ResetPassword(ctx, token, "a brand new password"); !errors.As(err, &invalidArgumentError) {
+		t.Errorf("got error %v, expected an InvalidArgumentError", err)
+	}
+
+	if !checkTestPassword(t, usersService, user.Username, testPassword) {
+		t.Error("expected the expired token not to change the password")
+	}
+}
+
+func TestRequestPasswordResetReplacesPendingToken(t *testing.T) {
+	db := newTestDB(t)
+
+	var mail bytes.Buffer
+
+	usersService := newTestUsersServiceWithMailer(t, db, NewWriterMailer(&mail, "test@example.com"))
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	firstToken := requestTestPasswordReset(t, usersService, &mail, user.Email)
+	secondToken := requestTestPasswordReset(t, usersService, &mail, user.Email)
+
+	var invalidArgumentError *InvalidArgumentError
+
+	if err := usersService.ResetPassword(ctx, firstToken, "a brand new password"); !errors.As(err, &invalidArgumentError) {
+		t.Errorf("got error %v for the first token, expected an InvalidArgumentError", err)
+	}
+
+	if err := usersService.ResetPassword(ctx, secondToken, "a brand new password"); err != nil {
+		t.Errorf("got error %v for the second token", err)
+	}
+}
+
+func TestRequestPasswordResetIgnoresUnknownEmail(t *testing.T) {
+	db := newTestDB(t)
+
+	var mail bytes.Buffer
+
+	usersService := newTestUsersServiceWithMailer(t, db, NewWriterMailer(&mail, "test@example.com"))
+
+	if err := usersService.RequestPasswordReset(context.Background(), uniqueName(t, "unknown-")+"@example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if mail.Len() != 0 {
+		t.Errorf("got mail %q, expected none", mail.String())
+	}
+}
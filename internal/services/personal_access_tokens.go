@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+const (
+	ScopeArticlesWrite = "articles:write"
+	ScopeCommentsWrite = "comments:write"
+	ScopeProfilesWrite = "profiles:write"
+)
+
+var Scopes = []string{ScopeArticlesWrite, ScopeCommentsWrite, ScopeProfilesWrite}
+
+// personalAccessTokenPrefix makes personal access tokens easy to spot by secret scanners.
+const personalAccessTokenPrefix = "rwpat_"
+
+// personalAccessTokenLastUsedPrecision avoids writing to the database on every request made with the same token.
+const personalAccessTokenLastUsedPrecision = time.Minute
+
+type CreatePersonalAccessToken struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+func NewCreatePersonalAccessToken(name string, scopes []string, expiresAt *time.Time) CreatePersonalAccessToken {
+	return CreatePersonalAccessToken{
+		Name:      name,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	}
+}
+
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, personalAccessTokenPrefix)
+}
+
+func PersonalAccessTokenScopes(personalAccessToken model.PersonalAccessToken) []string {
+	return strings.Fields(personalAccessToken.Scopes)
+}
+
+func (usersService *UsersService) CreatePersonalAccessToken(ctx context.Context, userId uuid.UUID, createPersonalAccessToken CreatePersonalAccessToken) (*model.PersonalAccessToken, *string, error) {
+	usersService.logger.InfoContext(ctx, "Creating personal access token", "userId", userId, "name", createPersonalAccessToken.Name, "scopes", createPersonalAccessToken.Scopes, "expiresAt", createPersonalAccessToken.ExpiresAt)
+
+	name := strings.TrimSpace(createPersonalAccessToken.Name)
+	if name == "" {
+		return nil, nil, &InvalidArgumentError{msg: "Personal access token name is required"}
+	}
+
+	if len(createPersonalAccessToken.Scopes) == 0 {
+		return nil, nil, &InvalidArgumentError{msg: "Personal access token must have at least one scope"}
+	}
+
+	for _, scope := range createPersonalAccessToken.Scopes {
+		if !slices.Contains(Scopes, scope) {
+			return nil, nil, &InvalidArgumentError{msg: fmt.Sprintf("Invalid scope %s. Valid scopes are %s", scope, strings.Join(Scopes, ", "))}
+		}
+	}
+
+	if createPersonalAccessToken.ExpiresAt != nil && createPersonalAccessToken.ExpiresAt.Before(time.Now()) {
+		return nil, nil, &InvalidArgumentError{msg: "Personal access token expiration must be in the future"}
+	}
+
+	var nameExistsDest struct {
+		NameExists bool
+	}
+
+	nameExistsStmt := SELECT(EXISTS(PersonalAccessToken.SELECT(PersonalAccessToken.ID).WHERE(PersonalAccessToken.UserID.EQ(UUID(userId)).AND(PersonalAccessToken.Name.EQ(String(name))))).AS("name_exists"))
+
+	err := nameExistsStmt.QueryContext(ctx, usersService.db, &nameExistsDest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if nameExistsDest.NameExists {
+		return nil, nil, &AlreadyExistsError{msg: fmt.Sprintf("Personal access token %s already exists", name)}
+	}
+
+	randomToken, err := generateToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	token := personalAccessTokenPrefix + *randomToken
+
+	var scopes []string
+	for _, scope := range createPersonalAccessToken.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	personalAccessToken := model.PersonalAccessToken{
+		UserID:    &userId,
+		Name:      name,
+		TokenHash: hashToken(token),
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: createPersonalAccessToken.ExpiresAt,
+	}
+
+	insertStmt := PersonalAccessToken.INSERT(PersonalAccessToken.UserID, PersonalAccessToken.Name, PersonalAccessToken.TokenHash, PersonalAccessToken.Scopes, PersonalAccessToken.ExpiresAt).MODEL(personalAccessToken).RETURNING(PersonalAccessToken.AllColumns)
+
+	if err = insertStmt.QueryContext(ctx, usersService.db, &personalAccessToken); err != nil {
+		return nil, nil, err
+	}
+
+	usersService.logger.InfoContext(ctx, "Personal access token created", "userId", userId, "personalAccessTokenId", personalAccessToken.ID)
+
+	return &personalAccessToken, &token, nil
+}
+
+func (usersService *UsersService) ListPersonalAccessTokens(ctx context.Context, userId uuid.UUID) (*[]model.PersonalAccessToken, error) {
+	personalAccessTokens := []model.PersonalAccessToken{}
+
+	listStmt := SELECT(PersonalAccessToken.AllColumns).FROM(PersonalAccessToken).WHERE(PersonalAccessToken.UserID.EQ(UUID(userId))).ORDER_BY(PersonalAccessToken.CreatedAt.DESC())
+
+	err := listStmt.QueryContext(ctx, usersService.db, &personalAccessTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	return &personalAccessTokens, nil
+}
+
+func (usersService *UsersService) RevokePersonalAccessToken(ctx context.Context, userId uuid.UUID, personalAccessTokenId uuid.UUID) error {
+	usersService.logger.InfoContext(ctx, "Revoking personal access token", "userId", userId, "personalAccessTokenId", personalAccessTokenId)
+
+	deleteStmt := PersonalAccessToken.DELETE().WHERE(PersonalAccessToken.ID.EQ(UUID(personalAccessTokenId)).AND(PersonalAccessToken.UserID.EQ(UUID(userId))))
+
+	sqlResult, err := deleteStmt.ExecContext(ctx, usersService.db)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return &NotFoundError{msg: fmt.Sprintf("Personal access token %s not found", personalAccessTokenId)}
+	}
+
+	return nil
+}
+
+func (usersService *UsersService) revokeAllPersonalAccessTokens(ctx context.Context, db qrm.Executable, userId uuid.UUID) error {
+	deleteStmt := PersonalAccessToken.DELETE().WHERE(PersonalAccessToken.UserID.EQ(UUID(userId)))
+
+	_, err := deleteStmt.ExecContext(ctx, db)
+
+	return err
+}
+
+func (usersService *UsersService) GetUserByPersonalAccessToken(ctx context.Context, token string) (*model.Users, *[]string, error) {
+	var personalAccessToken model.PersonalAccessToken
+
+	getTokenStmt := SELECT(PersonalAccessToken.AllColumns).FROM(PersonalAccessToken).WHERE(PersonalAccessToken.TokenHash.EQ(String(hashToken(token))))
+
+	err := getTokenStmt.QueryContext(ctx, usersService.db, &personalAccessToken)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil, &UnauthenticatedError{msg: "Invalid personal access token"}
+		}
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+
+	if personalAccessToken.ExpiresAt != nil && now.After(*personalAccessToken.ExpiresAt) {
+		return nil, nil, &UnauthenticatedError{msg: fmt.Sprintf("Personal access token %s has expired", personalAccessToken.ID)}
+	}
+
+	if personalAccessToken.LastUsedAt == nil || now.Sub(*personalAccessToken.LastUsedAt) > personalAccessTokenLastUsedPrecision {
+		updateLastUsedAtStmt := PersonalAccessToken.UPDATE(PersonalAccessToken.LastUsedAt).SET(TimestampzT(now)).WHERE(PersonalAccessToken.ID.EQ(UUID(personalAccessToken.ID)))
+
+		if _, err = updateLastUsedAtStmt.ExecContext(ctx, usersService.db); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	user, err := usersService.GetUserById(ctx, *personalAccessToken.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scopes := PersonalAccessTokenScopes(personalAccessToken)
+
+	return user, &scopes, nil
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log/slog"
+	"slices"
 
 	. "github.com/go-jet/jet/v2/postgres"
 	"github.com/google/uuid"
@@ -26,41 +28,82 @@ func NewProfilesService(db *sql.DB, logger *slog.Logger, usersService *UsersServ
 }
 
 type Profile struct {
-	UserID    uuid.UUID
-	Username  string
-	Bio       *string
-	Image     *string
-	Following bool
+	UserID          uuid.UUID
+	Username        string
+	Bio             *string
+	Image           *string
+	Private         bool
+	Following       bool
+	FollowRequested bool
+	FollowersCount  int
+	FollowingCount  int
 }
 
-func NewProfile(user model.Users, following bool) Profile {
+func NewProfile(user model.Users, following bool, followRequested bool, followersCount int, followingCount int) Profile {
 	return Profile{
-		UserID:    user.ID,
-		Username:  user.Username,
-		Bio:       user.Bio,
-		Image:     user.Image,
-		Following: following,
+		UserID:          user.ID,
+		Username:        user.Username,
+		Bio:             user.Bio,
+		Image:           user.Image,
+		Private:         user.IsPrivate,
+		Following:       following,
+		FollowRequested: followRequested,
+		FollowersCount:  followersCount,
+		FollowingCount:  followingCount,
 	}
 }
 
+type ListFollows struct {
+	Limit  *int
+	Offset *int
+}
+
 func (profilesService *ProfilesService) GetProfile(ctx context.Context, userId uuid.UUID, followerId *uuid.UUID) (*Profile, error) {
-	user, err := profilesService.usersService.GetUserById(ctx, userId)
+	profiles, err := profilesService.GetProfiles(ctx, []uuid.UUID{userId}, followerId)
 	if err != nil {
 		return nil, err
 	}
 
-	following := false
-	if followerId != nil {
-		isFollowing, err := profilesService.IsFollowing(ctx, *followerId, user.ID)
-		if err != nil {
-			return nil, err
+	if len(*profiles) == 0 {
+		return nil, &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
+	}
+
+	return &(*profiles)[0], nil
+}
+
+// GetProfiles keeps the order of userIds, leaving out the users that don't exist.
+func (profilesService *ProfilesService) GetProfiles(ctx context.Context, userIds []uuid.UUID, followerId *uuid.UUID) (*[]Profile, error) {
+	if len(userIds) == 0 {
+		return &[]Profile{}, nil
+	}
+
+	selectStmt := profilesService.selectProfiles(followerId).FROM(Users).WHERE(Users.ID.IN(uuidExpressions(userIds)...))
+
+	profiles, err := profilesService.queryProfiles(ctx, selectStmt)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[uuid.UUID]int, len(userIds))
+	for i, userId := range userIds {
+		if _, ok := positions[userId]; !ok {
+			positions[userId] = i
 		}
-		following = *isFollowing
 	}
 
-	profile := NewProfile(*user, following)
+	slices.SortFunc(*profiles, func(a, b Profile) int {
+		return positions[a.UserID] - positions[b.UserID]
+	})
+
+	return profiles, nil
+}
+
+func (profilesService *ProfilesService) ListFollowers(ctx context.Context, userId uuid.UUID, viewerId *uuid.UUID, listFollows ListFollows) (*[]Profile, *int, error) {
+	return profilesService.listFollows(ctx, Follow.FollowerID, Follow.FollowedID.EQ(UUID(userId)), viewerId, listFollows)
+}
 
-	return &profile, nil
+func (profilesService *ProfilesService) ListFollowing(ctx context.Context, userId uuid.UUID, viewerId *uuid.UUID, listFollows ListFollows) (*[]Profile, *int, error) {
+	return profilesService.listFollows(ctx, Follow.FollowedID, Follow.FollowerID.EQ(UUID(userId)), viewerId, listFollows)
 }
 
 func (profilesService *ProfilesService) FollowUser(ctx context.Context, followerId uuid.UUID, followedId uuid.UUID) error {
@@ -75,6 +118,10 @@ func (profilesService *ProfilesService) FollowUser(ctx context.Context, follower
 		return nil
 	}
 
+	if err = checkNotBlocked(ctx, profilesService.db, followedId, followerId); err != nil {
+		return err
+	}
+
 	follower, err := profilesService.usersService.GetUserById(ctx, followerId)
 	if err != nil {
 		return err
@@ -85,6 +132,10 @@ func (profilesService *ProfilesService) FollowUser(ctx context.Context, follower
 		return err
 	}
 
+	if followed.IsPrivate {
+		return profilesService.requestFollow(ctx, follower.ID, followed.ID)
+	}
+
 	follow := model.Follow{
 		FollowerID: &follower.ID,
 		FollowedID: &followed.ID,
@@ -102,6 +153,10 @@ func (profilesService *ProfilesService) FollowUser(ctx context.Context, follower
 func (profilesService *ProfilesService) UnfollowUser(ctx context.Context, followerId uuid.UUID, followedId uuid.UUID) error {
 	profilesService.logger.InfoContext(ctx, "Unfollowing user", "followerId", followerId, "followedId", followedId)
 
+	if err := profilesService.cancelFollowRequest(ctx, followerId, followedId); err != nil {
+		return err
+	}
+
 	isFollowing, err := profilesService.IsFollowing(ctx, followerId, followedId)
 	if err != nil {
 		return err
@@ -121,47 +176,90 @@ func (profilesService *ProfilesService) UnfollowUser(ctx context.Context, follow
 	return nil
 }
 
-func (profilesService *ProfilesService) ListFollowedProfiles(ctx context.Context, userId uuid.UUID) (*[]Profile, error) {
-	var followedIds []uuid.UUID
-
-	var follows []model.Follow
+func (profilesService *ProfilesService) IsFollowing(ctx context.Context, followerId uuid.UUID, followedId uuid.UUID) (*bool, error) {
+	var dest struct {
+		IsFollowing bool
+	}
 
-	listFollowedIdsStmt := SELECT(Follow.FollowedID).FROM(Follow).WHERE(Follow.FollowerID.EQ(UUID(userId)))
+	isFollowingStmt := SELECT(EXISTS(Follow.SELECT(Follow.ID).WHERE(Follow.FollowerID.EQ(UUID(followerId)).AND(Follow.FollowedID.EQ(UUID(followedId))))).AS("is_following"))
 
-	err := listFollowedIdsStmt.QueryContext(ctx, profilesService.db, &follows)
+	err := isFollowingStmt.QueryContext(ctx, profilesService.db, &dest)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, follow := range follows {
-		followedIds = append(followedIds, *follow.FollowedID)
+	return &dest.IsFollowing, nil
+}
+
+func (profilesService *ProfilesService) listFollows(ctx context.Context, profileColumn ColumnString, condition BoolExpression, viewerId *uuid.UUID, listFollows ListFollows) (*[]Profile, *int, error) {
+	from := Follow.INNER_JOIN(Users, Users.ID.EQ(profileColumn))
+
+	condition = condition.AND(Users.SuspendedAt.IS_NULL())
+
+	var countDest struct {
+		ProfilesCount int
 	}
 
-	users, err := profilesService.usersService.ListUsers(ctx, ListUsers{UserIDs: &followedIds})
+	countStmt := SELECT(COUNT(STAR).AS("profiles_count")).FROM(from).WHERE(condition)
+
+	err := countStmt.QueryContext(ctx, profilesService.db, &countDest)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	profiles := []Profile{}
+	selectStmt := profilesService.selectProfiles(viewerId).FROM(from).WHERE(condition).ORDER_BY(Follow.CreatedAt.DESC(), Follow.ID.DESC())
 
-	for _, user := range *users {
-		profiles = append(profiles, NewProfile(user, true))
+	if listFollows.Limit != nil {
+		selectStmt = selectStmt.LIMIT(int64(*listFollows.Limit))
 	}
 
-	return &profiles, nil
+	if listFollows.Offset != nil {
+		selectStmt = selectStmt.OFFSET(int64(*listFollows.Offset))
+	}
+
+	profiles, err := profilesService.queryProfiles(ctx, selectStmt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return profiles, &countDest.ProfilesCount, nil
 }
 
-func (profilesService *ProfilesService) IsFollowing(ctx context.Context, followerId uuid.UUID, followedId uuid.UUID) (*bool, error) {
-	var dest struct {
-		IsFollowing bool
+func (profilesService *ProfilesService) selectProfiles(followerId *uuid.UUID) SelectStatement {
+	following := Bool(false)
+	followRequested := Bool(false)
+	if followerId != nil {
+		following = EXISTS(Follow.SELECT(Follow.ID).WHERE(Follow.FollowerID.EQ(UUID(*followerId)).AND(Follow.FollowedID.EQ(Users.ID))))
+		followRequested = EXISTS(FollowRequest.SELECT(FollowRequest.ID).WHERE(FollowRequest.FollowerID.EQ(UUID(*followerId)).AND(FollowRequest.FollowedID.EQ(Users.ID))))
 	}
 
-	isFollowingStmt := SELECT(EXISTS(Follow.SELECT(Follow.ID).WHERE(Follow.FollowerID.EQ(UUID(followerId)).AND(Follow.FollowedID.EQ(UUID(followedId))))).AS("is_following"))
+	// Suspended users are hidden, so they aren't counted either.
+	followUser := Users.AS("follow_user")
 
-	err := isFollowingStmt.QueryContext(ctx, profilesService.db, &dest)
-	if err != nil {
+	followersCount := IntExp(SELECT(COUNT(STAR)).FROM(Follow.INNER_JOIN(followUser, followUser.ID.EQ(Follow.FollowerID))).WHERE(Follow.FollowedID.EQ(Users.ID).AND(followUser.SuspendedAt.IS_NULL())))
+
+	followingCount := IntExp(SELECT(COUNT(STAR)).FROM(Follow.INNER_JOIN(followUser, followUser.ID.EQ(Follow.FollowedID))).WHERE(Follow.FollowerID.EQ(Users.ID).AND(followUser.SuspendedAt.IS_NULL())))
+
+	return SELECT(Users.AllColumns, following.AS("following"), followRequested.AS("follow_requested"), followersCount.AS("followers_count"), followingCount.AS("following_count"))
+}
+
+func (profilesService *ProfilesService) queryProfiles(ctx context.Context, selectStmt SelectStatement) (*[]Profile, error) {
+	var dest []struct {
+		model.Users
+		Following       bool
+		FollowRequested bool
+		FollowersCount  int
+		FollowingCount  int
+	}
+
+	if err := selectStmt.QueryContext(ctx, profilesService.db, &dest); err != nil {
 		return nil, err
 	}
 
-	return &dest.IsFollowing, nil
+	profiles := make([]Profile, len(dest))
+	for i, row := range dest {
+		profiles[i] = NewProfile(row.Users, row.Following, row.FollowRequested, row.FollowersCount, row.FollowingCount)
+	}
+
+	return &profiles, nil
 }
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFollowCountsLeaveOutSuspendedUsers(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+	follower := createTestUser(t, usersService)
+	suspendedFollower := createTestUser(t, usersService)
+	followed := createTestUser(t, usersService)
+	suspendedFollowed := createTestUser(t, usersService)
+
+	if err := profilesService.FollowUser(ctx, follower.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := profilesService.FollowUser(ctx, suspendedFollower.ID, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := profilesService.FollowUser(ctx, user.ID, followed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := profilesService.FollowUser(ctx, user.ID, suspendedFollowed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := usersService.SuspendUser(ctx, suspendedFollower.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := usersService.SuspendUser(ctx, suspendedFollowed.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := profilesService.GetProfile(ctx, user.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if profile.FollowersCount != 1 || profile.FollowingCount != 1 {
+		t.Errorf("got %d followers and %d following, expected 1 and 1", profile.FollowersCount, profile.FollowingCount)
+	}
+
+	followers, followersCount, err := profilesService.ListFollowers(ctx, user.ID, nil, ListFollows{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *followersCount != 1 || len(*followers) != 1 || (*followers)[0].Username != follower.Username {
+		t.Errorf("got %d followers %v, expected only %s", *followersCount, *followers, follower.Username)
+	}
+}
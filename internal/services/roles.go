@@ -0,0 +1,39 @@
+package services
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var Roles = []string{RoleUser, RoleModerator, RoleAdmin}
+
+type Permission string
+
+const (
+	PermissionDeleteAnyContent Permission = "content:delete"
+	PermissionUpdateAnyArticle Permission = "articles:update"
+	PermissionManageUsers      Permission = "users:manage"
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleUser:      {},
+	RoleModerator: {PermissionDeleteAnyContent},
+	RoleAdmin:     {PermissionDeleteAnyContent, PermissionUpdateAnyArticle, PermissionManageUsers},
+}
+
+func HasPermission(user model.Users, permission Permission) bool {
+	return slices.Contains(rolePermissions[user.Role], permission)
+}
+
+// CanActOn lets users act on what they own, and on what others own if their role grants the permission.
+func CanActOn(user model.Users, ownerId uuid.UUID, permission Permission) bool {
+	return user.ID == ownerId || HasPermission(user, permission)
+}
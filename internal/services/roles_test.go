@@ -0,0 +1,34 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+)
+
+func TestCanActOn(t *testing.T) {
+	ownerId := uuid.New()
+
+	testCases := []struct {
+		name       string
+		user       model.Users
+		permission Permission
+		want       bool
+	}{
+		{name: "owner", user: model.Users{ID: ownerId, Role: RoleUser}, permission: PermissionDeleteAnyContent, want: true},
+		{name: "user", user: model.Users{ID: uuid.New(), Role: RoleUser}, permission: PermissionDeleteAnyContent},
+		{name: "moderator deleting", user: model.Users{ID: uuid.New(), Role: RoleModerator}, permission: PermissionDeleteAnyContent, want: true},
+		{name: "moderator updating", user: model.Users{ID: uuid.New(), Role: RoleModerator}, permission: PermissionUpdateAnyArticle},
+		{name: "admin updating", user: model.Users{ID: uuid.New(), Role: RoleAdmin}, permission: PermissionUpdateAnyArticle, want: true},
+		{name: "unknown role", user: model.Users{ID: uuid.New(), Role: "owner"}, permission: PermissionDeleteAnyContent},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if got := CanActOn(testCase.user, ownerId, testCase.permission); got != testCase.want {
+				t.Errorf("got %t, expected %t", got, testCase.want)
+			}
+		})
+	}
+}
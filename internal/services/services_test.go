@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+)
+
+// newTestDB skips the test unless TEST_DATABASE_URL points to a database with all the migrations applied.
+func newTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	databaseURL := os.Getenv("TEST_DATABASE_URL")
+	if databaseURL == "" {
+		tb.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() {
+		db.Close()
+	})
+
+	if err = db.PingContext(context.Background()); err != nil {
+		tb.Fatal(err)
+	}
+
+	return db
+}
+
+func newTestLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+var testArgon2idParams = Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+}
+
+func newTestUsersService(tb testing.TB, db *sql.DB) *UsersService {
+	tb.Helper()
+
+	return newTestUsersServiceWithMailer(tb, db, NewWriterMailer(io.Discard, "test@example.com"))
+}
+
+func newTestUsersServiceWithMailer(tb testing.TB, db *sql.DB, mailer Mailer) *UsersService {
+	tb.Helper()
+
+	usersServiceJWT, err := NewUsersServiceJWT("test", []JWTKey{NewHMACJWTKey("test", []byte("test-secret"), time.Time{})}, 900, 3600)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	passwordHasher := NewArgon2idPasswordHasher(testArgon2idParams)
+
+	usersService := NewUsersService(db, &usersServiceJWT, mailer, &passwordHasher, UsersServiceConfig{
+		AccountDeletionGracePeriodSeconds:     3600,
+		AppURL:                                "http://localhost:4200",
+		EmailVerificationTokenValidForSeconds: 3600,
+		PasswordResetTokenValidForSeconds:     3600,
+		TOTPEncryptionKey:                     bytes.Repeat([]byte{1}, 32),
+		TOTPIssuer:                            "test",
+		UsernameReuseCooldownSeconds:          3600,
+	}, newTestLogger())
+
+	return &usersService
+}
+
+// uniqueName lets tests share the database without cleaning it up.
+func uniqueName(tb testing.TB, prefix string) string {
+	tb.Helper()
+
+	suffix := make([]byte, 6)
+
+	if _, err := rand.Read(suffix); err != nil {
+		tb.Fatal(err)
+	}
+
+	return prefix + hex.EncodeToString(suffix)
+}
+
+func uniqueIPAddress(tb testing.TB) string {
+	tb.Helper()
+
+	address := make([]byte, 3)
+
+	if _, err := rand.Read(address); err != nil {
+		tb.Fatal(err)
+	}
+
+	return fmt.Sprintf("10.%d.%d.%d", address[0], address[1], address[2])
+}
+
+const testPassword = "correct horse battery staple"
+
+func createTestUser(tb testing.TB, usersService *UsersService) *model.Users {
+	tb.Helper()
+
+	username := uniqueName(tb, "user-")
+
+	user, err := usersService.RegisterUser(context.Background(), NewRegisterUser(username+"@example.com", username, testPassword))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return user
+}
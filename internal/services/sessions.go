@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// RefreshSession revokes the whole session when a refresh token is used twice.
+func (usersService *UsersService) RefreshSession(ctx context.Context, refreshToken string) (*model.Users, *Token, error) {
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	var currentRefreshToken model.UserSessionRefreshToken
+
+	getRefreshTokenStmt := SELECT(UserSessionRefreshToken.AllColumns).FROM(UserSessionRefreshToken).WHERE(UserSessionRefreshToken.TokenHash.EQ(String(hashToken(refreshToken)))).FOR(UPDATE())
+
+	err = getRefreshTokenStmt.QueryContext(ctx, tx, &currentRefreshToken)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, nil, &UnauthenticatedError{msg: "Invalid refresh token"}
+		}
+		return nil, nil, err
+	}
+
+	var session model.UserSession
+
+	getSessionStmt := SELECT(UserSession.AllColumns).FROM(UserSession).WHERE(UserSession.ID.EQ(UUID(currentRefreshToken.SessionID))).FOR(UPDATE())
+
+	if err = getSessionStmt.QueryContext(ctx, tx, &session); err != nil {
+		return nil, nil, err
+	}
+
+	if session.RevokedAt != nil {
+		return nil, nil, &UnauthenticatedError{msg: fmt.Sprintf("Session %s is revoked", session.ID)}
+	}
+
+	if currentRefreshToken.UsedAt != nil {
+		usersService.logger.WarnContext(ctx, "Refresh token reuse detected. Revoking session", "userId", session.UserID, "sessionId", session.ID)
+
+		if err = usersService.revokeSession(ctx, tx, session.ID); err != nil {
+			return nil, nil, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, &UnauthenticatedError{msg: fmt.Sprintf("Refresh token reuse detected. Session %s has been revoked", session.ID)}
+	}
+
+	now := time.Now().UTC()
+
+	if now.After(currentRefreshToken.ExpiresAt) {
+		return nil, nil, &UnauthenticatedError{msg: "Refresh token has expired"}
+	}
+
+	markRefreshTokenUsedStmt := UserSessionRefreshToken.UPDATE(UserSessionRefreshToken.UsedAt).SET(TimestampzT(now)).WHERE(UserSessionRefreshToken.ID.EQ(UUID(currentRefreshToken.ID)))
+
+	if _, err = markRefreshTokenUsedStmt.ExecContext(ctx, tx); err != nil {
+		return nil, nil, err
+	}
+
+	newRefreshToken, err := usersService.createRefreshToken(ctx, tx, session.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := usersService.GetUserById(ctx, *session.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := usersService.signAccessToken(*user, session.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, &Token{
+		AccessToken:  *accessToken,
+		RefreshToken: *newRefreshToken,
+	}, nil
+}
+
+func (usersService *UsersService) RevokeSession(ctx context.Context, token string) error {
+	claims, err := usersService.parseToken(token)
+	if err != nil {
+		return err
+	}
+
+	sessionId, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return err
+	}
+
+	usersService.logger.InfoContext(ctx, "Revoking session", "userId", claims.Subject, "sessionId", sessionId)
+
+	return usersService.revokeSession(ctx, usersService.db, sessionId)
+}
+
+func (usersService *UsersService) RevokeAllSessions(ctx context.Context, userId uuid.UUID) error {
+	usersService.logger.InfoContext(ctx, "Revoking all sessions", "userId", userId)
+
+	return usersService.revokeAllSessions(ctx, usersService.db, userId)
+}
+
+func (usersService *UsersService) revokeAllSessions(ctx context.Context, db qrm.Executable, userId uuid.UUID) error {
+	now := time.Now().UTC()
+
+	revokeAllSessionsStmt := UserSession.UPDATE(UserSession.RevokedAt).SET(TimestampzT(now)).WHERE(UserSession.UserID.EQ(UUID(userId)).AND(UserSession.RevokedAt.IS_NULL()))
+
+	if _, err := revokeAllSessionsStmt.ExecContext(ctx, db); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (usersService *UsersService) revokeSession(ctx context.Context, db qrm.Executable, sessionId uuid.UUID) error {
+	now := time.Now().UTC()
+
+	revokeSessionStmt := UserSession.UPDATE(UserSession.RevokedAt).SET(TimestampzT(now)).WHERE(UserSession.ID.EQ(UUID(sessionId)).AND(UserSession.RevokedAt.IS_NULL()))
+
+	if _, err := revokeSessionStmt.ExecContext(ctx, db); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (usersService *UsersService) createRefreshToken(ctx context.Context, db qrm.Queryable, sessionId uuid.UUID) (*string, error) {
+	refreshToken, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionRefreshToken := model.UserSessionRefreshToken{
+		SessionID: &sessionId,
+		TokenHash: hashToken(*refreshToken),
+		ExpiresAt: time.Now().UTC().Add(time.Second * time.Duration(usersService.jwt.refreshTokenValidForSeconds)),
+	}
+
+	insertRefreshTokenStmt := UserSessionRefreshToken.INSERT(UserSessionRefreshToken.SessionID, UserSessionRefreshToken.TokenHash, UserSessionRefreshToken.ExpiresAt).MODEL(sessionRefreshToken).RETURNING(UserSessionRefreshToken.AllColumns)
+
+	if err = insertRefreshTokenStmt.QueryContext(ctx, db, &sessionRefreshToken); err != nil {
+		return nil, err
+	}
+
+	return refreshToken, nil
+}
+
+func (usersService *UsersService) signAccessToken(user model.Users, sessionId uuid.UUID) (*string, error) {
+	now := time.Now()
+
+	exp := now.Add(time.Second * time.Duration(usersService.jwt.validForSeconds))
+
+	return usersService.jwt.sign(tokenClaims{
+		SessionID: sessionId.String(),
+		Role:      user.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(exp),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    usersService.jwt.iss,
+			Subject:   user.ID.String(),
+		},
+	})
+}
+
+func (usersService *UsersService) parseToken(token string) (*tokenClaims, error) {
+	var claims tokenClaims
+
+	if err := usersService.jwt.parse(token, &claims); err != nil {
+		return nil, &UnauthenticatedError{msg: err.Error()}
+	}
+
+	if claims.SessionID == "" {
+		return nil, &UnauthenticatedError{msg: "Token has no session"}
+	}
+
+	return &claims, nil
+}
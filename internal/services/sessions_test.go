@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRefreshSessionRotatesRefreshToken(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	token, err := usersService.GetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, refreshedToken, err := usersService.RefreshSession(ctx, token.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if refreshedToken.RefreshToken == token.RefreshToken {
+		t.Error("expected a new refresh token")
+	}
+
+	if _, err = usersService.GetUserByToken(ctx, refreshedToken.AccessToken); err != nil {
+		t.Errorf("got error %v for the new access token", err)
+	}
+
+	if _, _, err = usersService.RefreshSession(ctx, refreshedToken.RefreshToken); err != nil {
+		t.Errorf("got error %v for the new refresh token", err)
+	}
+}
+
+func TestRefreshSessionReuseRevokesSession(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	token, err := usersService.GetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, refreshedToken, err := usersService.RefreshSession(ctx, token.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unauthenticatedError *UnauthenticatedError
+
+	if _, _, err = usersService.RefreshSession(ctx, token.RefreshToken); !errors.As(err, &unauthenticatedError) {
+		t.Fatalf("got error %v reusing the refresh token, expected an UnauthenticatedError", err)
+	}
+
+	// Whoever reused the refresh token may have the rotated one too, so it must stop working.
+	if _, _, err = usersService.RefreshSession(ctx, refreshedToken.RefreshToken); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v for the rotated refresh token, expected an UnauthenticatedError", err)
+	}
+
+	if _, err = usersService.GetUserByToken(ctx, refreshedToken.AccessToken); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v for the rotated access token, expected an UnauthenticatedError", err)
+	}
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	firstToken, err := usersService.GetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondToken, err := usersService.GetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = usersService.RevokeAllSessions(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []*Token{firstToken, secondToken} {
+		if _, _, err = usersService.RefreshSession(ctx, token.RefreshToken); err == nil {
+			t.Error("expected the refresh token to be revoked")
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/google/uuid"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+// How much each reason to suggest a user adds to their score.
+const (
+	suggestionFollowedByFollowedWeight = 3
+	suggestionFavoritedAuthorWeight    = 2
+	suggestionSharedTagWeight          = 1
+)
+
+// SuggestProfiles tops up the scored suggestions with the most followed users, so new users get suggestions too.
+func (profilesService *ProfilesService) SuggestProfiles(ctx context.Context, userId uuid.UUID, limit int) (*[]Profile, error) {
+	scores := map[uuid.UUID]int{}
+
+	FollowOfFollow := Follow.AS("follow_of_follow")
+
+	followedByFollowedStmt := SELECT(FollowOfFollow.FollowedID.AS("user_id"), COUNT(STAR).AS("score")).
+		FROM(Follow.INNER_JOIN(FollowOfFollow, FollowOfFollow.FollowerID.EQ(Follow.FollowedID))).
+		WHERE(Follow.FollowerID.EQ(UUID(userId)).AND(isSuggestible(FollowOfFollow.FollowedID, userId))).
+		GROUP_BY(FollowOfFollow.FollowedID)
+
+	favoritedAuthorsStmt := SELECT(Article.AuthorID.AS("user_id"), COUNT(STAR).AS("score")).
+		FROM(ArticleFavorite.INNER_JOIN(Article, Article.ID.EQ(ArticleFavorite.ArticleID))).
+		WHERE(ArticleFavorite.UserID.EQ(UUID(userId)).AND(isSuggestible(Article.AuthorID, userId))).
+		GROUP_BY(Article.AuthorID)
+
+	userArticleIds := SELECT(Article.ID).FROM(Article).WHERE(Article.AuthorID.EQ(UUID(userId)))
+	favoritedArticleIds := SELECT(ArticleFavorite.ArticleID).FROM(ArticleFavorite).WHERE(ArticleFavorite.UserID.EQ(UUID(userId)))
+	userTagIds := SELECT(ArticleArticleTag.ArticleTagID).FROM(ArticleArticleTag).WHERE(ArticleArticleTag.ArticleID.IN(userArticleIds).OR(ArticleArticleTag.ArticleID.IN(favoritedArticleIds)))
+
+	sharedTagsStmt := SELECT(Article.AuthorID.AS("user_id"), COUNT(DISTINCT(ArticleArticleTag.ArticleTagID)).AS("score")).
+		FROM(Article.INNER_JOIN(ArticleArticleTag, ArticleArticleTag.ArticleID.EQ(Article.ID))).
+		WHERE(ArticleArticleTag.ArticleTagID.IN(userTagIds).AND(isSuggestible(Article.AuthorID, userId))).
+		GROUP_BY(Article.AuthorID)
+
+	sources := []struct {
+		stmt   SelectStatement
+		weight int
+	}{
+		{followedByFollowedStmt, suggestionFollowedByFollowedWeight},
+		{favoritedAuthorsStmt, suggestionFavoritedAuthorWeight},
+		{sharedTagsStmt, suggestionSharedTagWeight},
+	}
+
+	for _, source := range sources {
+		var dest []struct {
+			UserID uuid.UUID
+			Score  int
+		}
+
+		if err := source.stmt.QueryContext(ctx, profilesService.db, &dest); err != nil {
+			return nil, err
+		}
+
+		for _, score := range dest {
+			scores[score.UserID] += score.Score * source.weight
+		}
+	}
+
+	suggestedIds := make([]uuid.UUID, 0, len(scores))
+	for suggestedId := range scores {
+		suggestedIds = append(suggestedIds, suggestedId)
+	}
+
+	slices.SortFunc(suggestedIds, func(a, b uuid.UUID) int {
+		if scores[a] != scores[b] {
+			return scores[b] - scores[a]
+		}
+		return strings.Compare(a.String(), b.String())
+	})
+
+	if len(suggestedIds) > limit {
+		suggestedIds = suggestedIds[:limit]
+	}
+
+	if len(suggestedIds) < limit {
+		popularIds, err := profilesService.listPopularUserIds(ctx, userId, suggestedIds, limit-len(suggestedIds))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestedIds = append(suggestedIds, popularIds...)
+	}
+
+	return profilesService.GetProfiles(ctx, suggestedIds, &userId)
+}
+
+func (profilesService *ProfilesService) listPopularUserIds(ctx context.Context, userId uuid.UUID, excludedIds []uuid.UUID, limit int) ([]uuid.UUID, error) {
+	condition := isSuggestible(Users.ID, userId)
+
+	if len(excludedIds) > 0 {
+		condition = condition.AND(Users.ID.NOT_IN(uuidExpressions(excludedIds)...))
+	}
+
+	followersCount := IntExp(SELECT(COUNT(STAR)).FROM(Follow).WHERE(Follow.FollowedID.EQ(Users.ID)))
+
+	popularStmt := SELECT(Users.ID.AS("user_id"), followersCount.AS("score")).
+		FROM(Users).
+		WHERE(condition).
+		ORDER_BY(followersCount.DESC(), Users.CreatedAt.ASC(), Users.ID.ASC()).
+		LIMIT(int64(limit))
+
+	var dest []struct {
+		UserID uuid.UUID
+		Score  int
+	}
+
+	if err := popularStmt.QueryContext(ctx, profilesService.db, &dest); err != nil {
+		return nil, err
+	}
+
+	popularIds := make([]uuid.UUID, len(dest))
+	for i, score := range dest {
+		popularIds[i] = score.UserID
+	}
+
+	return popularIds, nil
+}
+
+func isSuggestible(candidateId ColumnString, userId uuid.UUID) BoolExpression {
+	return candidateId.NOT_EQ(UUID(userId)).
+		AND(NOT(EXISTS(Follow.SELECT(Follow.ID).WHERE(Follow.FollowerID.EQ(UUID(userId)).AND(Follow.FollowedID.EQ(candidateId)))))).
+		AND(NOT(EXISTS(FollowRequest.SELECT(FollowRequest.ID).WHERE(FollowRequest.FollowerID.EQ(UUID(userId)).AND(FollowRequest.FollowedID.EQ(candidateId)))))).
+		AND(NOT(isBlockedBetween(UUID(userId), candidateId))).
+		AND(NOT(isMutedBy(candidateId, userId))).
+		AND(isNotSuspended(candidateId))
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSuggestProfiles(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+	profilesService := NewProfilesService(db, newTestLogger(), usersService)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+	followed := createTestUser(t, usersService)
+	followedByFollowed := createTestUser(t, usersService)
+	blocked := createTestUser(t, usersService)
+
+	follows := []struct {
+		followerId uuid.UUID
+		followedId uuid.UUID
+	}{
+		{user.ID, followed.ID},
+		{followed.ID, followedByFollowed.ID},
+		{followed.ID, blocked.ID},
+	}
+
+	for _, follow := range follows {
+		if err := profilesService.FollowUser(ctx, follow.followerId, follow.followedId); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := profilesService.BlockUser(ctx, user.ID, blocked.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := profilesService.SuggestProfiles(ctx, user.ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var usernames []string
+	for _, profile := range *profiles {
+		usernames = append(usernames, profile.Username)
+	}
+
+	if len(usernames) == 0 || usernames[0] != followedByFollowed.Username {
+		t.Errorf("got suggestions %v, expected %s first", usernames, followedByFollowed.Username)
+	}
+
+	for _, username := range []string{user.Username, followed.Username, blocked.Username} {
+		if slices.Contains(usernames, username) {
+			t.Errorf("got suggestions %v, expected %s to be left out", usernames, username)
+		}
+	}
+}
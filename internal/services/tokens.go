@@ -0,0 +1,26 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+func generateToken() (*string, error) {
+	tokenBytes := make([]byte, 32)
+
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	return &token, nil
+}
+
+func hashToken(token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(tokenHash[:])
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	totpDigits = 6
+	totpPeriod = 30
+	totpSkew   = 1
+)
+
+var totpSecretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateTOTPSecret() (*string, error) {
+	secretBytes := make([]byte, 20)
+
+	if _, err := rand.Read(secretBytes); err != nil {
+		return nil, err
+	}
+
+	secret := totpSecretEncoding.EncodeToString(secretBytes)
+
+	return &secret, nil
+}
+
+func makeTOTPURI(issuer string, accountName string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	label := url.PathEscape(fmt.Sprintf("%s:%s", issuer, accountName))
+
+	// Authenticator apps don't decode "+" as a space in the query.
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, strings.ReplaceAll(query.Encode(), "+", "%20"))
+}
+
+// validateTOTP returns the period (step) the code matched, so it can't be replayed.
+func validateTOTP(secret string, code string, now time.Time) (int64, bool) {
+	secretBytes, err := totpSecretEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	code = strings.ReplaceAll(code, " ", "")
+
+	currentStep := now.Unix() / totpPeriod
+
+	for step := currentStep - totpSkew; step <= currentStep+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(generateTOTPCode(secretBytes, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func generateTOTPCode(secret []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, truncated%modulo)
+}
+
+// encryptTOTPSecret binds the encrypted secret to the user, so it can't be copied to another account.
+func encryptTOTPSecret(key []byte, userId uuid.UUID, secret string) (*string, error) {
+	aead, err := newTOTPSecretAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	encryptedSecret := base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(secret), userId[:]))
+
+	return &encryptedSecret, nil
+}
+
+func decryptTOTPSecret(key []byte, userId uuid.UUID, encryptedSecret string) (*string, error) {
+	aead, err := newTOTPSecretAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealedSecret, err := base64.StdEncoding.DecodeString(encryptedSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealedSecret) < aead.NonceSize() {
+		return nil, errors.New("encrypted TOTP secret is too short")
+	}
+
+	secretBytes, err := aead.Open(nil, sealedSecret[:aead.NonceSize()], sealedSecret[aead.NonceSize():], userId[:])
+	if err != nil {
+		return nil, err
+	}
+
+	secret := string(secretBytes)
+
+	return &secret, nil
+}
+
+func newTOTPSecretAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The RFC 6238 test secret, "12345678901234567890".
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerateTOTPCode(t *testing.T) {
+	secret, err := totpSecretEncoding.DecodeString(testTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The RFC 6238 SHA-1 test vectors, truncated to 6 digits.
+	testCases := []struct {
+		unix int64
+		code string
+	}{
+		{unix: 59, code: "287082"},
+		{unix: 1111111109, code: "081804"},
+		{unix: 1111111111, code: "050471"},
+		{unix: 1234567890, code: "005924"},
+		{unix: 2000000000, code: "279037"},
+		{unix: 20000000000, code: "353130"},
+	}
+
+	for _, testCase := range testCases {
+		if code := generateTOTPCode(secret, testCase.unix/totpPeriod); code != testCase.code {
+			t.Errorf("got code %s at %d, expected %s", code, testCase.unix, testCase.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret, err := totpSecretEncoding.DecodeString(testTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1234567890, 0)
+	currentStep := now.Unix() / totpPeriod
+
+	testCases := []struct {
+		name   string
+		secret string
+		code   string
+		step   int64
+		ok     bool
+	}{
+		{name: "current period", secret: testTOTPSecret, code: generateTOTPCode(secret, currentStep), step: currentStep, ok: true},
+		{name: "previous period", secret: testTOTPSecret, code: generateTOTPCode(secret, currentStep-1), step: currentStep - 1, ok: true},
+		{name: "next period", secret: testTOTPSecret, code: generateTOTPCode(secret, currentStep+1), step: currentStep + 1, ok: true},
+		{name: "two periods ago", secret: testTOTPSecret, code: generateTOTPCode(secret, currentStep-2)},
+		{name: "two periods ahead", secret: testTOTPSecret, code: generateTOTPCode(secret, currentStep+2)},
+		{name: "spaces", secret: testTOTPSecret, code: "005 924", step: currentStep, ok: true},
+		{name: "lowercase secret", secret: strings.ToLower(testTOTPSecret), code: "005924", step: currentStep, ok: true},
+		{name: "wrong code", secret: testTOTPSecret, code: "123456"},
+		{name: "empty code", secret: testTOTPSecret, code: ""},
+		{name: "invalid secret", secret: "not base32!", code: "005924"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			step, ok := validateTOTP(testCase.secret, testCase.code, now)
+			if ok != testCase.ok || step != testCase.step {
+				t.Errorf("got step %d and ok %t, expected %d and %t", step, ok, testCase.step, testCase.ok)
+			}
+		})
+	}
+}
+
+func TestMakeTOTPURI(t *testing.T) {
+	uri, err := url.Parse(makeTOTPURI("Real World", "jake", testTOTPSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Real World:jake" {
+		t.Errorf("got URI %s", uri)
+	}
+
+	if strings.Contains(uri.RawQuery, "+") {
+		t.Errorf("got query %s, expected spaces to be encoded as %%20", uri.RawQuery)
+	}
+
+	query := uri.Query()
+
+	if query.Get("secret") != testTOTPSecret || query.Get("issuer") != "Real World" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("got query %s", uri.RawQuery)
+	}
+}
+
+func TestEncryptTOTPSecret(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	userId := uuid.New()
+
+	encryptedSecret, err := encryptTOTPSecret(key, userId, testTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(*encryptedSecret, testTOTPSecret) {
+		t.Fatalf("got encrypted secret %s containing the secret", *encryptedSecret)
+	}
+
+	secret, err := decryptTOTPSecret(key, userId, *encryptedSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *secret != testTOTPSecret {
+		t.Errorf("got secret %s, expected %s", *secret, testTOTPSecret)
+	}
+
+	otherEncryptedSecret, err := encryptTOTPSecret(key, userId, testTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *otherEncryptedSecret == *encryptedSecret {
+		t.Error("expected every encryption to use a new nonce")
+	}
+}
+
+func TestDecryptTOTPSecretRejectsTampering(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	userId := uuid.New()
+
+	encryptedSecret, err := encryptTOTPSecret(key, userId, testTOTPSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tamperedSecret := []byte(*encryptedSecret)
+	if tamperedSecret[20] == 'A' {
+		tamperedSecret[20] = 'B'
+	} else {
+		tamperedSecret[20] = 'A'
+	}
+
+	testCases := []struct {
+		name            string
+		key             []byte
+		userId          uuid.UUID
+		encryptedSecret string
+	}{
+		{name: "other key", key: bytes.Repeat([]byte{2}, 32), userId: userId, encryptedSecret: *encryptedSecret},
+		{name: "other user", key: key, userId: uuid.New(), encryptedSecret: *encryptedSecret},
+		{name: "tampered", key: key, userId: userId, encryptedSecret: string(tamperedSecret)},
+		{name: "too short", key: key, userId: userId, encryptedSecret: "AAAA"},
+		{name: "not base64", key: key, userId: userId, encryptedSecret: "not base64!"},
+		{name: "plaintext", key: key, userId: userId, encryptedSecret: testTOTPSecret},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if _, err := decryptTOTPSecret(testCase.key, testCase.userId, testCase.encryptedSecret); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+const (
+	loginChallengeValidFor          = 5 * time.Minute
+	loginChallengeMaxFailedAttempts = 5
+	recoveryCodesCount              = 10
+	recoveryCodeBytes               = 16
+)
+
+type TwoFactorSetup struct {
+	Secret string
+	URI    string
+}
+
+type TwoFactorChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+func (usersService *UsersService) SetupTwoFactor(ctx context.Context, userId uuid.UUID) (*TwoFactorSetup, error) {
+	usersService.logger.InfoContext(ctx, "Setting up two-factor authentication", "userId", userId)
+
+	user, err := usersService.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	isTwoFactorEnabled, err := usersService.IsTwoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if *isTwoFactorEnabled {
+		return nil, &AlreadyExistsError{msg: "Two-factor authentication is already enabled"}
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedSecret, err := encryptTOTPSecret(usersService.config.TOTPEncryptionKey, user.ID, *secret)
+	if err != nil {
+		return nil, err
+	}
+
+	userTotp := model.UserTotp{
+		UserID:          &user.ID,
+		EncryptedSecret: *encryptedSecret,
+	}
+
+	upsertTotpStmt := UserTotp.INSERT(UserTotp.UserID, UserTotp.EncryptedSecret).MODEL(userTotp).ON_CONFLICT(UserTotp.UserID).DO_UPDATE(SET(UserTotp.EncryptedSecret.SET(UserTotp.EXCLUDED.EncryptedSecret), UserTotp.LastUsedStep.SET(IntExp(NULL))))
+
+	if _, err = upsertTotpStmt.ExecContext(ctx, usersService.db); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret: *secret,
+		URI:    makeTOTPURI(usersService.config.TOTPIssuer, user.Username, *secret),
+	}, nil
+}
+
+func (usersService *UsersService) EnableTwoFactor(ctx context.Context, userId uuid.UUID, code string) (*[]string, error) {
+	usersService.logger.InfoContext(ctx, "Enabling two-factor authentication", "userId", userId)
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	userTotp, err := usersService.getUserTotp(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if userTotp.EnabledAt != nil {
+		return nil, &AlreadyExistsError{msg: "Two-factor authentication is already enabled"}
+	}
+
+	now := time.Now().UTC()
+
+	step, ok, err := usersService.validateUserTOTP(userTotp, code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, &InvalidArgumentError{msg: "Invalid two-factor authentication code"}
+	}
+
+	userTotp.EnabledAt = &now
+	userTotp.LastUsedStep = &step
+
+	enableTotpStmt := UserTotp.UPDATE(UserTotp.EnabledAt, UserTotp.LastUsedStep).MODEL(userTotp).WHERE(UserTotp.ID.EQ(UUID(userTotp.ID)))
+
+	if _, err = enableTotpStmt.ExecContext(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := usersService.createRecoveryCodes(ctx, tx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func (usersService *UsersService) DisableTwoFactor(ctx context.Context, userId uuid.UUID, code string) error {
+	usersService.logger.InfoContext(ctx, "Disabling two-factor authentication", "userId", userId)
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	userTotp, err := usersService.getUserTotp(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	if userTotp.EnabledAt != nil {
+		isValidCode, err := usersService.checkTwoFactorCode(ctx, tx, userTotp, code, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+
+		if !*isValidCode {
+			return &UnauthenticatedError{msg: fmt.Sprintf("Invalid two-factor authentication code for user %s", userId)}
+		}
+	}
+
+	deleteTotpStmt := UserTotp.DELETE().WHERE(UserTotp.UserID.EQ(UUID(userId)))
+
+	if _, err = deleteTotpStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	deleteRecoveryCodesStmt := UserRecoveryCode.DELETE().WHERE(UserRecoveryCode.UserID.EQ(UUID(userId)))
+
+	if _, err = deleteRecoveryCodesStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (usersService *UsersService) IsTwoFactorEnabled(ctx context.Context, userId uuid.UUID) (*bool, error) {
+	var dest struct {
+		IsTwoFactorEnabled bool
+	}
+
+	isTwoFactorEnabledStmt := SELECT(EXISTS(UserTotp.SELECT(UserTotp.ID).WHERE(UserTotp.UserID.EQ(UUID(userId)).AND(UserTotp.EnabledAt.IS_NOT_NULL()))).AS("is_two_factor_enabled"))
+
+	err := isTwoFactorEnabledStmt.QueryContext(ctx, usersService.db, &dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dest.IsTwoFactorEnabled, nil
+}
+
+func (usersService *UsersService) CreateLoginChallenge(ctx context.Context, userId uuid.UUID) (*TwoFactorChallenge, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	loginChallenge := model.LoginChallenge{
+		UserID:    &userId,
+		TokenHash: hashToken(*token),
+		ExpiresAt: time.Now().UTC().Add(loginChallengeValidFor),
+	}
+
+	insertChallengeStmt := LoginChallenge.INSERT(LoginChallenge.UserID, LoginChallenge.TokenHash, LoginChallenge.ExpiresAt).MODEL(loginChallenge)
+
+	if _, err = insertChallengeStmt.ExecContext(ctx, usersService.db); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorChallenge{
+		Token:     *token,
+		ExpiresAt: loginChallenge.ExpiresAt,
+	}, nil
+}
+
+func (usersService *UsersService) CompleteLoginChallenge(ctx context.Context, challengeToken string, code string, ipAddress string) (*model.Users, error) {
+	now := time.Now().UTC()
+
+	if err := usersService.checkIPAddressFailedLogins(ctx, ipAddress, now); err != nil {
+		return nil, err
+	}
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var loginChallenge model.LoginChallenge
+
+	getChallengeStmt := SELECT(LoginChallenge.AllColumns).FROM(LoginChallenge).WHERE(LoginChallenge.TokenHash.EQ(String(hashToken(challengeToken)))).FOR(UPDATE())
+
+	err = getChallengeStmt.QueryContext(ctx, tx, &loginChallenge)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &UnauthenticatedError{msg: "Invalid login challenge"}
+		}
+		return nil, err
+	}
+
+	if loginChallenge.UsedAt != nil || now.After(loginChallenge.ExpiresAt) || loginChallenge.FailedAttempts >= loginChallengeMaxFailedAttempts {
+		return nil, &UnauthenticatedError{msg: "Login challenge is expired or has already been used"}
+	}
+
+	user, err := usersService.GetUserById(ctx, *loginChallenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	userTotp, err := usersService.getUserTotp(ctx, tx, *loginChallenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if userTotp.EnabledAt == nil {
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("Two-factor authentication is not enabled for user %s", loginChallenge.UserID)}
+	}
+
+	isValidCode, err := usersService.checkTwoFactorCode(ctx, tx, userTotp, code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !*isValidCode {
+		incrementFailedAttemptsStmt := LoginChallenge.UPDATE(LoginChallenge.FailedAttempts).SET(LoginChallenge.FailedAttempts.ADD(Int(1))).WHERE(LoginChallenge.ID.EQ(UUID(loginChallenge.ID)))
+
+		if _, err = incrementFailedAttemptsStmt.ExecContext(ctx, tx); err != nil {
+			return nil, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return nil, err
+		}
+
+		if err = usersService.recordFailedLogin(ctx, user, ipAddress, now); err != nil {
+			return nil, err
+		}
+
+		return nil, &UnauthenticatedError{msg: fmt.Sprintf("Invalid two-factor authentication code for user %s", user.ID)}
+	}
+
+	if err = usersService.checkAccountLockout(ctx, user.ID, now); err != nil {
+		return nil, err
+	}
+
+	markChallengeUsedStmt := LoginChallenge.UPDATE(LoginChallenge.UsedAt).SET(TimestampzT(now)).WHERE(LoginChallenge.ID.EQ(UUID(loginChallenge.ID)))
+
+	if _, err = markChallengeUsedStmt.ExecContext(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if err = usersService.recordLoginAttempt(ctx, &user.ID, user.Email, ipAddress, true); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (usersService *UsersService) checkTwoFactorCode(ctx context.Context, tx qrm.Executable, userTotp *model.UserTotp, code string, now time.Time) (*bool, error) {
+	isValidCode := false
+
+	step, ok, err := usersService.validateUserTOTP(userTotp, code, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok && (userTotp.LastUsedStep == nil || step > *userTotp.LastUsedStep) {
+		updateLastUsedStepStmt := UserTotp.UPDATE(UserTotp.LastUsedStep).SET(Int(step)).WHERE(UserTotp.ID.EQ(UUID(userTotp.ID)))
+
+		if _, err := updateLastUsedStepStmt.ExecContext(ctx, tx); err != nil {
+			return nil, err
+		}
+
+		isValidCode = true
+		return &isValidCode, nil
+	}
+
+	useRecoveryCodeStmt := UserRecoveryCode.UPDATE(UserRecoveryCode.UsedAt).SET(TimestampzT(now)).WHERE(
+		UserRecoveryCode.UserID.EQ(UUID(userTotp.UserID)).
+			AND(UserRecoveryCode.CodeHash.EQ(String(hashToken(normalizeRecoveryCode(code))))).
+			AND(UserRecoveryCode.UsedAt.IS_NULL()))
+
+	sqlResult, err := useRecoveryCodeStmt.ExecContext(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if rowsAffected > 0 {
+		usersService.logger.InfoContext(ctx, "Recovery code used", "userId", userTotp.UserID)
+		isValidCode = true
+	}
+
+	return &isValidCode, nil
+}
+
+func (usersService *UsersService) validateUserTOTP(userTotp *model.UserTotp, code string, now time.Time) (int64, bool, error) {
+	secret, err := decryptTOTPSecret(usersService.config.TOTPEncryptionKey, *userTotp.UserID, userTotp.EncryptedSecret)
+	if err != nil {
+		return 0, false, err
+	}
+
+	step, ok := validateTOTP(*secret, code, now)
+
+	return step, ok, nil
+}
+
+func (usersService *UsersService) getUserTotp(ctx context.Context, db qrm.Queryable, userId uuid.UUID) (*model.UserTotp, error) {
+	var userTotp model.UserTotp
+
+	getTotpStmt := SELECT(UserTotp.AllColumns).FROM(UserTotp).WHERE(UserTotp.UserID.EQ(UUID(userId))).FOR(UPDATE())
+
+	err := getTotpStmt.QueryContext(ctx, db, &userTotp)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &NotFoundError{msg: fmt.Sprintf("Two-factor authentication is not set up for user %s", userId)}
+		}
+		return nil, err
+	}
+
+	return &userTotp, nil
+}
+
+func (usersService *UsersService) createRecoveryCodes(ctx context.Context, db qrm.Executable, userId uuid.UUID) (*[]string, error) {
+	deleteRecoveryCodesStmt := UserRecoveryCode.DELETE().WHERE(UserRecoveryCode.UserID.EQ(UUID(userId)))
+
+	if _, err := deleteRecoveryCodesStmt.ExecContext(ctx, db); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, recoveryCodesCount)
+	userRecoveryCodes := make([]model.UserRecoveryCode, recoveryCodesCount)
+
+	for i := range recoveryCodes {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes[i] = *recoveryCode
+
+		userRecoveryCodes[i] = model.UserRecoveryCode{
+			UserID:   &userId,
+			CodeHash: hashToken(normalizeRecoveryCode(recoveryCodes[i])),
+		}
+	}
+
+	insertRecoveryCodesStmt := UserRecoveryCode.INSERT(UserRecoveryCode.UserID, UserRecoveryCode.CodeHash).MODELS(userRecoveryCodes)
+
+	if _, err := insertRecoveryCodesStmt.ExecContext(ctx, db); err != nil {
+		return nil, err
+	}
+
+	return &recoveryCodes, nil
+}
+
+func generateRecoveryCode() (*string, error) {
+	codeBytes := make([]byte, recoveryCodeBytes)
+
+	if _, err := rand.Read(codeBytes); err != nil {
+		return nil, err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(codeBytes))
+
+	recoveryCode := fmt.Sprintf("%s-%s", code[:len(code)/2], code[len(code)/2:])
+
+	return &recoveryCode, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
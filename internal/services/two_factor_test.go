@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+)
+
+var recoveryCodeRegexp = regexp.MustCompile(`^[a-z2-7]{13}-[a-z2-7]{13}$`)
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	recoveryCodes := map[string]bool{}
+
+	for i := 0; i < 100; i++ {
+		recoveryCode, err := generateRecoveryCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !recoveryCodeRegexp.MatchString(*recoveryCode) {
+			t.Fatalf("got recovery code %s", *recoveryCode)
+		}
+
+		if recoveryCodes[*recoveryCode] {
+			t.Fatalf("got recovery code %s twice", *recoveryCode)
+		}
+		recoveryCodes[*recoveryCode] = true
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	testCases := []struct {
+		code     string
+		expected string
+	}{
+		{code: "abcdefghijklm-nopqrstuvwxyz", expected: "abcdefghijklmnopqrstuvwxyz"},
+		{code: "ABCDEFGHIJKLM-NOPQRSTUVWXYZ", expected: "abcdefghijklmnopqrstuvwxyz"},
+		{code: " abcdefghijklmnopqrstuvwxyz\n", expected: "abcdefghijklmnopqrstuvwxyz"},
+		{code: "abcd-efgh-ijkl", expected: "abcdefghijkl"},
+	}
+
+	for _, testCase := range testCases {
+		if normalized := normalizeRecoveryCode(testCase.code); normalized != testCase.expected {
+			t.Errorf("got %s for %q, expected %s", normalized, testCase.code, testCase.expected)
+		}
+	}
+}
+
+func enableTestTwoFactor(t *testing.T, usersService *UsersService) (*model.Users, []string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	setup, err := usersService.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := totpSecretEncoding.DecodeString(setup.Secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recoveryCodes, err := usersService.EnableTwoFactor(ctx, user.ID, generateTOTPCode(secret, time.Now().Unix()/totpPeriod))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return user, *recoveryCodes
+}
+
+func TestSetupTwoFactorEncryptsSecret(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	setup, err := usersService.SetupTwoFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userTotp, err := usersService.getUserTotp(ctx, db, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if userTotp.EncryptedSecret == setup.Secret {
+		t.Error("expected the secret to be encrypted")
+	}
+
+	secret, err := decryptTOTPSecret(usersService.config.TOTPEncryptionKey, user.ID, userTotp.EncryptedSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *secret != setup.Secret {
+		t.Errorf("got secret %s, expected %s", *secret, setup.Secret)
+	}
+}
+
+func TestDisableTwoFactorRequiresCode(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user, recoveryCodes := enableTestTwoFactor(t, usersService)
+
+	var unauthenticatedError *UnauthenticatedError
+
+	for _, code := range []string{"", "000000", "not-a-recovery-code"} {
+		if err := usersService.DisableTwoFactor(ctx, user.ID, code); !errors.As(err, &unauthenticatedError) {
+			t.Errorf("got error %v for code %q, expected an UnauthenticatedError", err, code)
+		}
+	}
+
+	isTwoFactorEnabled, err := usersService.IsTwoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !*isTwoFactorEnabled {
+		t.Fatal("expected two-factor authentication to stay enabled")
+	}
+
+	if err := usersService.DisableTwoFactor(ctx, user.ID, recoveryCodes[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	isTwoFactorEnabled, err = usersService.IsTwoFactorEnabled(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *isTwoFactorEnabled {
+		t.Error("expected two-factor authentication to be disabled")
+	}
+}
+
+func TestCompleteLoginChallengeRecoveryCodeIsSingleUse(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user, recoveryCodes := enableTestTwoFactor(t, usersService)
+
+	ipAddress := uniqueIPAddress(t)
+
+	challenge, err := usersService.CreateLoginChallenge(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = usersService.CompleteLoginChallenge(ctx, challenge.Token, recoveryCodes[0], ipAddress); err != nil {
+		t.Fatal(err)
+	}
+
+	challenge, err = usersService.CreateLoginChallenge(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var unauthenticatedError *UnauthenticatedError
+
+	if _, err = usersService.CompleteLoginChallenge(ctx, challenge.Token, recoveryCodes[0], ipAddress); !errors.As(err, &unauthenticatedError) {
+		t.Errorf("got error %v reusing the recovery code, expected an UnauthenticatedError", err)
+	}
+
+	if _, err = usersService.CompleteLoginChallenge(ctx, challenge.Token, recoveryCodes[1], ipAddress); err != nil {
+		t.Errorf("got error %v for another recovery code", err)
+	}
+}
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+type SearchUsers struct {
+	// Query matches part of the email or username, ignoring case.
+	Query  *string
+	Limit  *int
+	Offset *int
+}
+
+var likePatternEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (usersService *UsersService) SearchUsers(ctx context.Context, searchUsers SearchUsers) (*[]model.Users, *int, error) {
+	condition := Bool(true)
+
+	if searchUsers.Query != nil && *searchUsers.Query != "" {
+		pattern := String("%" + likePatternEscaper.Replace(strings.ToLower(*searchUsers.Query)) + "%")
+		condition = condition.AND(LOWER(Users.Email).LIKE(pattern).OR(LOWER(Users.Username).LIKE(pattern)))
+	}
+
+	var countDest struct {
+		UsersCount int
+	}
+
+	countStmt := SELECT(COUNT(STAR).AS("users_count")).FROM(Users).WHERE(condition)
+
+	err := countStmt.QueryContext(ctx, usersService.db, &countDest)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users := []model.Users{}
+
+	searchStmt := SELECT(Users.AllColumns).FROM(Users).WHERE(condition).ORDER_BY(Users.CreatedAt.ASC(), Users.ID.ASC())
+
+	if searchUsers.Limit != nil {
+		searchStmt = searchStmt.LIMIT(int64(*searchUsers.Limit))
+	}
+
+	if searchUsers.Offset != nil {
+		searchStmt = searchStmt.OFFSET(int64(*searchUsers.Offset))
+	}
+
+	if err = searchStmt.QueryContext(ctx, usersService.db, &users); err != nil {
+		return nil, nil, err
+	}
+
+	return &users, &countDest.UsersCount, nil
+}
+
+func (usersService *UsersService) SuspendUser(ctx context.Context, userId uuid.UUID) (*model.Users, error) {
+	usersService.logger.InfoContext(ctx, "Suspending user", "userId", userId)
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	user := model.Users{}
+
+	suspendStmt := Users.UPDATE(Users.SuspendedAt).SET(COALESCE(Users.SuspendedAt, TimestampzT(time.Now().UTC()))).WHERE(Users.ID.EQ(UUID(userId))).RETURNING(Users.AllColumns)
+
+	if err = suspendStmt.QueryContext(ctx, tx, &user); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
+		}
+		return nil, err
+	}
+
+	if err = usersService.revokeAllSessions(ctx, tx, userId); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (usersService *UsersService) UnsuspendUser(ctx context.Context, userId uuid.UUID) (*model.Users, error) {
+	usersService.logger.InfoContext(ctx, "Unsuspending user", "userId", userId)
+
+	user := model.Users{}
+
+	unsuspendStmt := Users.UPDATE(Users.SuspendedAt).SET(TimestampzExp(NULL)).WHERE(Users.ID.EQ(UUID(userId))).RETURNING(Users.AllColumns)
+
+	if err := unsuspendStmt.QueryContext(ctx, usersService.db, &user); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// ForcePasswordReset replaces the password with a random one and emails the user a link to choose a new one.
+func (usersService *UsersService) ForcePasswordReset(ctx context.Context, userId uuid.UUID) error {
+	usersService.logger.InfoContext(ctx, "Forcing password reset", "userId", userId)
+
+	user, err := usersService.GetUserById(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	password, err := generateToken()
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := usersService.hashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	updatePasswordStmt := Users.UPDATE(Users.PasswordHash, Users.UpdatedAt).SET(String(*passwordHash), TimestampzT(time.Now().UTC())).WHERE(Users.ID.EQ(UUID(userId)))
+
+	if _, err = updatePasswordStmt.ExecContext(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = usersService.revokeAllSessions(ctx, tx, userId); err != nil {
+		return err
+	}
+
+	if err = usersService.revokeAllPersonalAccessTokens(ctx, tx, userId); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	return usersService.sendPasswordReset(ctx, *user, "An administrator reset the password of your account.")
+}
+
+func (usersService *UsersService) UpdateUserRole(ctx context.Context, userId uuid.UUID, role string) (*model.Users, error) {
+	usersService.logger.InfoContext(ctx, "Updating user role", "userId", userId, "role", role)
+
+	if !slices.Contains(Roles, role) {
+		return nil, &InvalidArgumentError{msg: fmt.Sprintf("Invalid role %s. Valid roles are %s", role, strings.Join(Roles, ", "))}
+	}
+
+	user := model.Users{}
+
+	updateStmt := Users.UPDATE(Users.Role, Users.UpdatedAt).SET(String(role), TimestampzT(time.Now().UTC())).WHERE(Users.ID.EQ(UUID(userId))).RETURNING(Users.AllColumns)
+
+	if err := updateStmt.QueryContext(ctx, usersService.db, &user); err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteUser deletes the user and, through the foreign keys, everything they created.
+func (usersService *UsersService) DeleteUser(ctx context.Context, userId uuid.UUID) error {
+	usersService.logger.InfoContext(ctx, "Deleting user", "userId", userId)
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	tagIds, err := listArticlesTagIds(ctx, tx, Article.AuthorID.EQ(UUID(userId)))
+	if err != nil {
+		return err
+	}
+
+	deleteStmt := Users.DELETE().WHERE(Users.ID.EQ(UUID(userId)))
+
+	sqlResult, err := deleteStmt.ExecContext(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := sqlResult.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
+	}
+
+	if err = deleteUnusedTags(ctx, tx, tagIds); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	usersService.logger.InfoContext(ctx, "User deleted", "userId", userId)
+
+	return nil
+}
+
+func isNotSuspended(userId ColumnString) BoolExpression {
+	return userId.NOT_IN(SELECT(Users.ID).FROM(Users).WHERE(Users.SuspendedAt.IS_NOT_NULL()))
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/go-jet/jet/v2/postgres"
+	"github.com/go-jet/jet/v2/qrm"
+	"github.com/google/uuid"
+	"github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/model"
+	. "github.com/marcusmonteirodesouza/realworld-backend-go-jet-postgresql/.gen/realworld/public/table"
+)
+
+func (usersService *UsersService) GetUserByPreviousUsername(ctx context.Context, username string) (*model.Users, error) {
+	user := model.Users{}
+
+	selectStmt := SELECT(Users.AllColumns).
+		FROM(UsernameHistory.INNER_JOIN(Users, Users.ID.EQ(UsernameHistory.UserID))).
+		WHERE(LOWER(UsernameHistory.Username).EQ(LOWER(String(username)))).
+		ORDER_BY(UsernameHistory.CreatedAt.DESC()).
+		LIMIT(1)
+
+	err := selectStmt.QueryContext(ctx, usersService.db, &user)
+	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &NotFoundError{msg: fmt.Sprintf("Username %s not found", username)}
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetUserByCurrentOrPreviousUsername also returns whether the username is a previous one.
+func (usersService *UsersService) GetUserByCurrentOrPreviousUsername(ctx context.Context, username string) (*model.Users, bool, error) {
+	user, err := usersService.GetUserByUsername(ctx, username)
+	if err == nil {
+		return user, false, nil
+	}
+
+	var notFoundError *NotFoundError
+	if !errors.As(err, &notFoundError) {
+		return nil, false, err
+	}
+
+	user, err = usersService.GetUserByPreviousUsername(ctx, username)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return user, true, nil
+}
+
+func (usersService *UsersService) recordUsernameChange(ctx context.Context, db qrm.Executable, userId uuid.UUID, previousUsername string) error {
+	insertStmt := UsernameHistory.INSERT(UsernameHistory.UserID, UsernameHistory.Username).VALUES(UUID(userId), String(previousUsername))
+
+	_, err := insertStmt.ExecContext(ctx, db)
+
+	return err
+}
+
+// checkUsernameCooldown keeps links to the previous owner from silently pointing to someone else.
+func (usersService *UsersService) checkUsernameCooldown(ctx context.Context, username string, userId *uuid.UUID) error {
+	releasedAfter := time.Now().UTC().Add(-time.Second * time.Duration(usersService.config.UsernameReuseCooldownSeconds))
+
+	condition := LOWER(UsernameHistory.Username).EQ(LOWER(String(username))).AND(UsernameHistory.CreatedAt.GT(TimestampzT(releasedAfter)))
+	if userId != nil {
+		condition = condition.AND(UsernameHistory.UserID.NOT_EQ(UUID(*userId)))
+	}
+
+	var recentlyReleasedDest struct {
+		RecentlyReleased bool
+	}
+
+	recentlyReleasedStmt := SELECT(EXISTS(UsernameHistory.SELECT(UsernameHistory.ID).WHERE(condition)).AS("recently_released"))
+
+	if err := recentlyReleasedStmt.QueryContext(ctx, usersService.db, &recentlyReleasedDest); err != nil {
+		return err
+	}
+
+	if recentlyReleasedDest.RecentlyReleased {
+		return &AlreadyExistsError{msg: "Username is taken"}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUsernameHistory(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+	previousUsername := user.Username
+
+	newUsername := uniqueName(t, "renamed-")
+
+	if _, err := usersService.UpdateUser(ctx, user.ID, UpdateUser{Username: &newUsername}); err != nil {
+		t.Fatal(err)
+	}
+
+	foundUser, isPreviousUsername, err := usersService.GetUserByCurrentOrPreviousUsername(ctx, previousUsername)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if foundUser.ID != user.ID || !isPreviousUsername {
+		t.Errorf("got user %s, previous username %t, expected %s by its previous username", foundUser.ID, isPreviousUsername, user.ID)
+	}
+
+	foundUser, isPreviousUsername, err = usersService.GetUserByCurrentOrPreviousUsername(ctx, newUsername)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if foundUser.ID != user.ID || isPreviousUsername {
+		t.Errorf("got user %s, previous username %t, expected %s by its current username", foundUser.ID, isPreviousUsername, user.ID)
+	}
+
+	var alreadyExistsError *AlreadyExistsError
+
+	if _, err = usersService.RegisterUser(ctx, NewRegisterUser(uniqueName(t, "other-")+"@example.com", previousUsername, testPassword)); !errors.As(err, &alreadyExistsError) {
+		t.Errorf("got error %v taking a username given up during the cooldown, expected an AlreadyExistsError", err)
+	}
+
+	if _, err = usersService.UpdateUser(ctx, user.ID, UpdateUser{Username: &previousUsername}); err != nil {
+		t.Errorf("got error %v taking back the previous username", err)
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"log/slog"
 	"net/mail"
 	"net/url"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	. "github.com/go-jet/jet/v2/postgres"
@@ -19,35 +22,53 @@ import (
 )
 
 type UsersService struct {
-	db     *sql.DB
-	jwt    *UsersServiceJWT
-	logger *slog.Logger
+	config         UsersServiceConfig
+	db             *sql.DB
+	jwt            *UsersServiceJWT
+	logger         *slog.Logger
+	mailer         Mailer
+	passwordHasher PasswordHasher
 }
 
-type UsersServiceJWT struct {
-	iss             string
-	key             []byte
-	parser          jwt.Parser
-	signinMethod    jwt.SigningMethod
-	validForSeconds int
+type UsersServiceConfig struct {
+	AccountDeletionGracePeriodSeconds     int
+	AppURL                                string
+	EmailVerificationTokenValidForSeconds int
+	PasswordResetTokenValidForSeconds     int
+	ReservedUsernames                     []string
+	TOTPEncryptionKey                     []byte
+	TOTPIssuer                            string
+	UsernameReuseCooldownSeconds          int
 }
 
-func NewUsersService(db *sql.DB, jwt *UsersServiceJWT, logger *slog.Logger) UsersService {
+func NewUsersService(db *sql.DB, jwt *UsersServiceJWT, mailer Mailer, passwordHasher PasswordHasher, config UsersServiceConfig, logger *slog.Logger) UsersService {
 	return UsersService{
-		db:     db,
-		jwt:    jwt,
-		logger: logger,
+		config:         config,
+		db:             db,
+		jwt:            jwt,
+		logger:         logger,
+		mailer:         mailer,
+		passwordHasher: passwordHasher,
 	}
 }
 
-func NewUsersServiceJWT(iss string, key []byte, validForSeconds int) UsersServiceJWT {
-	return UsersServiceJWT{
-		iss:             iss,
-		key:             key,
-		parser:          *jwt.NewParser(jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired(), jwt.WithIssuedAt(), jwt.WithIssuer(iss)),
-		signinMethod:    jwt.SigningMethodHS256,
-		validForSeconds: validForSeconds,
-	}
+const (
+	minUsernameLength = 3
+	maxUsernameLength = 32
+)
+
+var usernameInvalidCharacters = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+type Token struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+type tokenClaims struct {
+	SessionID string `json:"sid"`
+	// Role is informational, permissions are checked against the current role of the user.
+	Role string `json:"role,omitempty"`
+	jwt.RegisteredClaims
 }
 
 type RegisterUser struct {
@@ -69,26 +90,27 @@ type ListUsers struct {
 }
 
 type UpdateUser struct {
-	Email    *string
-	Username *string
-	Password *string
-	Bio      *string
-	Image    *string
+	Email     *string
+	Username  *string
+	Password  *string
+	Bio       *string
+	Image     *string
+	IsPrivate *bool
 }
 
 func (usersService *UsersService) RegisterUser(ctx context.Context, registerUser RegisterUser) (*model.Users, error) {
 	usersService.logger.InfoContext(ctx, "Registering user", "email", registerUser.Email, "username", registerUser.Username)
 
-	err := usersService.validateEmail(ctx, registerUser.Email)
+	err := usersService.validateEmail(ctx, registerUser.Email, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = usersService.validateUsername(ctx, registerUser.Username); err != nil {
+	if err = usersService.validateUsername(ctx, registerUser.Username, nil); err != nil {
 		return nil, err
 	}
 
-	passwordHash, err := usersService.hashPassword(ctx, registerUser.Password)
+	passwordHash, err := usersService.hashPassword(registerUser.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +151,7 @@ func (usersService *UsersService) GetUserById(ctx context.Context, userId uuid.U
 func (usersService *UsersService) GetUserByEmail(ctx context.Context, email string) (*model.Users, error) {
 	user := model.Users{}
 
-	selectStmt := SELECT(Users.AllColumns).FROM(Users).WHERE(Users.Email.EQ(String(email)))
+	selectStmt := SELECT(Users.AllColumns).FROM(Users).WHERE(LOWER(Users.Email).EQ(LOWER(String(email))))
 
 	err := selectStmt.QueryContext(ctx, usersService.db, &user)
 	if err != nil {
@@ -145,7 +167,7 @@ func (usersService *UsersService) GetUserByEmail(ctx context.Context, email stri
 func (usersService *UsersService) GetUserByUsername(ctx context.Context, username string) (*model.Users, error) {
 	user := model.Users{}
 
-	selectStmt := SELECT(Users.AllColumns).FROM(Users).WHERE(Users.Username.EQ(String(username)))
+	selectStmt := SELECT(Users.AllColumns).FROM(Users).WHERE(LOWER(Users.Username).EQ(LOWER(String(username))))
 
 	err := selectStmt.QueryContext(ctx, usersService.db, &user)
 	if err != nil {
@@ -159,27 +181,34 @@ func (usersService *UsersService) GetUserByUsername(ctx context.Context, usernam
 }
 
 func (usersService *UsersService) GetUserByToken(ctx context.Context, token string) (*model.Users, error) {
-	jwt, err := usersService.jwt.parser.Parse(token, func(t *jwt.Token) (interface{}, error) { return usersService.jwt.key, nil })
+	claims, err := usersService.parseToken(token)
 	if err != nil {
 		return nil, err
 	}
 
-	userIdString, err := jwt.Claims.GetSubject()
+	userId, err := uuid.Parse(claims.Subject)
 	if err != nil {
 		return nil, err
 	}
 
-	userId, err := uuid.Parse(userIdString)
+	sessionId, err := uuid.Parse(claims.SessionID)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := usersService.GetUserById(ctx, userId)
+	user := model.Users{}
+
+	selectStmt := SELECT(Users.AllColumns).FROM(Users.INNER_JOIN(UserSession, UserSession.UserID.EQ(Users.ID))).WHERE(Users.ID.EQ(UUID(userId)).AND(UserSession.ID.EQ(UUID(sessionId))).AND(UserSession.RevokedAt.IS_NULL()))
+
+	err = selectStmt.QueryContext(ctx, usersService.db, &user)
 	if err != nil {
+		if errors.Is(err, qrm.ErrNoRows) {
+			return nil, &UnauthenticatedError{msg: fmt.Sprintf("Session %s not found or revoked", sessionId)}
+		}
 		return nil, err
 	}
 
-	return user, nil
+	return &user, nil
 }
 
 func (usersService *UsersService) ListUsers(ctx context.Context, listUsers ListUsers) (*[]model.Users, error) {
@@ -212,7 +241,7 @@ func (usersService *UsersService) ListUsers(ctx context.Context, listUsers ListU
 }
 
 func (usersService *UsersService) UpdateUser(ctx context.Context, userId uuid.UUID, updateUser UpdateUser) (*model.Users, error) {
-	usersService.logger.InfoContext(ctx, "Updating user", "userId", userId, "email", updateUser.Email, "username", updateUser.Username, "bio", updateUser.Bio, "image", updateUser.Image, "isUpdatingPassword", updateUser.Password != nil)
+	usersService.logger.InfoContext(ctx, "Updating user", "userId", userId, "email", updateUser.Email, "username", updateUser.Username, "bio", updateUser.Bio, "image", updateUser.Image, "isPrivate", updateUser.IsPrivate, "isUpdatingPassword", updateUser.Password != nil)
 
 	user, err := usersService.GetUserById(ctx, userId)
 	if err != nil {
@@ -220,15 +249,18 @@ func (usersService *UsersService) UpdateUser(ctx context.Context, userId uuid.UU
 	}
 
 	if updateUser.Email != nil && *updateUser.Email != user.Email {
-		if err = usersService.validateEmail(ctx, *updateUser.Email); err != nil {
+		if err = usersService.validateEmail(ctx, *updateUser.Email, &user.ID); err != nil {
 			return nil, err
 		}
 
 		user.Email = *updateUser.Email
+		user.EmailVerifiedAt = nil
 	}
 
+	previousUsername := user.Username
+
 	if updateUser.Username != nil && *updateUser.Username != user.Username {
-		if err = usersService.validateUsername(ctx, *updateUser.Username); err != nil {
+		if err = usersService.validateUsername(ctx, *updateUser.Username, &user.ID); err != nil {
 			return nil, err
 		}
 
@@ -236,7 +268,7 @@ func (usersService *UsersService) UpdateUser(ctx context.Context, userId uuid.UU
 	}
 
 	if updateUser.Password != nil {
-		passwordHash, err := usersService.hashPassword(ctx, *updateUser.Password)
+		passwordHash, err := usersService.hashPassword(*updateUser.Password)
 		if err != nil {
 			return nil, err
 		}
@@ -244,11 +276,11 @@ func (usersService *UsersService) UpdateUser(ctx context.Context, userId uuid.UU
 		user.PasswordHash = *passwordHash
 	}
 
-	if updateUser.Bio != user.Bio {
+	if updateUser.Bio != nil {
 		user.Bio = updateUser.Bio
 	}
 
-	if updateUser.Image != user.Image {
+	if updateUser.Image != nil && (user.Image == nil || *updateUser.Image != *user.Image) {
 		if err = usersService.validateImage(*updateUser.Image); err != nil {
 			return nil, err
 		}
@@ -256,80 +288,145 @@ func (usersService *UsersService) UpdateUser(ctx context.Context, userId uuid.UU
 		user.Image = updateUser.Image
 	}
 
+	wasPrivate := user.IsPrivate
+
+	if updateUser.IsPrivate != nil {
+		user.IsPrivate = *updateUser.IsPrivate
+	}
+
 	now := time.Now().UTC()
 
 	user.UpdatedAt = &now
 
-	updateStmt := Users.UPDATE(Users.Email, Users.Username, Users.PasswordHash, Users.Bio, Users.Image, Users.UpdatedAt).MODEL(user).WHERE(Users.ID.EQ(UUID(user.ID))).RETURNING(Users.AllColumns)
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	updateStmt := Users.UPDATE(Users.Email, Users.EmailVerifiedAt, Users.Username, Users.PasswordHash, Users.Bio, Users.Image, Users.IsPrivate, Users.UpdatedAt).MODEL(user).WHERE(Users.ID.EQ(UUID(user.ID))).RETURNING(Users.AllColumns)
+
+	if err = updateStmt.QueryContext(ctx, tx, user); err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(user.Username, previousUsername) {
+		if err = usersService.recordUsernameChange(ctx, tx, user.ID, previousUsername); err != nil {
+			return nil, err
+		}
+	}
+
+	// Users who stop being private have nobody left to approve their pending followers.
+	if wasPrivate && !user.IsPrivate {
+		if err = approveFollowRequests(ctx, tx, user.ID); err != nil {
+			return nil, err
+		}
+	}
 
-	if err = updateStmt.QueryContext(ctx, usersService.db, user); err != nil {
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return user, nil
 }
 
+// CheckPassword rehashes correct passwords whose hash is outdated.
 func (usersService *UsersService) CheckPassword(ctx context.Context, userId uuid.UUID, password string) (*bool, error) {
-	var dest struct {
-		IsCorrectPassword bool
+	user, err := usersService.GetUserById(ctx, userId)
+	if err != nil {
+		return nil, err
 	}
 
-	checkPasswordStmt := SELECT(Users.PasswordHash.EQ(StringExp(Func("crypt", String(password), Users.PasswordHash))).AS("is_correct_password")).FROM(Users).WHERE(Users.ID.EQ(UUID(userId)))
-
-	err := checkPasswordStmt.QueryContext(ctx, usersService.db, &dest)
+	isCorrectPassword, needsRehash, err := usersService.passwordHasher.Verify(password, user.PasswordHash)
 	if err != nil {
-		if errors.Is(err, qrm.ErrNoRows) {
-			return nil, &NotFoundError{msg: fmt.Sprintf("User %s not found", userId)}
-		}
 		return nil, err
 	}
 
-	return &dest.IsCorrectPassword, nil
+	if isCorrectPassword && needsRehash {
+		usersService.logger.InfoContext(ctx, "Rehashing password", "userId", userId)
+
+		passwordHash, err := usersService.passwordHasher.Hash(password)
+		if err != nil {
+			return nil, err
+		}
+
+		// Only replace the hash that was verified, in case the password was changed in the meantime.
+		rehashStmt := Users.UPDATE(Users.PasswordHash).SET(String(passwordHash)).WHERE(Users.ID.EQ(UUID(userId)).AND(Users.PasswordHash.EQ(String(user.PasswordHash))))
+
+		if _, err = rehashStmt.ExecContext(ctx, usersService.db); err != nil {
+			return nil, err
+		}
+	}
+
+	return &isCorrectPassword, nil
 }
 
-func (usersService *UsersService) GetToken(ctx context.Context, user *model.Users) (*string, error) {
-	now := time.Now()
+func (usersService *UsersService) GetToken(ctx context.Context, user *model.Users) (*Token, error) {
+	if user.SuspendedAt != nil {
+		return nil, &PermissionDeniedError{msg: fmt.Sprintf("User %s is suspended", user.ID)}
+	}
+
+	tx, err := usersService.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	session := model.UserSession{
+		UserID: &user.ID,
+	}
 
-	exp := now.Add(time.Second * time.Duration(usersService.jwt.validForSeconds))
+	insertSessionStmt := UserSession.INSERT(UserSession.UserID).MODEL(session).RETURNING(UserSession.AllColumns)
 
-	token := jwt.NewWithClaims(usersService.jwt.signinMethod, jwt.MapClaims{
-		"exp": exp.Unix(),
-		"iat": now.Unix(),
-		"iss": usersService.jwt.iss,
-		"sub": user.ID,
-	})
+	if err = insertSessionStmt.QueryContext(ctx, tx, &session); err != nil {
+		return nil, err
+	}
 
-	jwt, err := token.SignedString(usersService.jwt.key)
+	if user.DeletionRequestedAt != nil {
+		if err = usersService.cancelAccountDeletion(ctx, tx, user.ID); err != nil {
+			return nil, err
+		}
+		user.DeletionRequestedAt = nil
+	}
+
+	refreshToken, err := usersService.createRefreshToken(ctx, tx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	usersService.logger.InfoContext(ctx, "Session created", "userId", user.ID, "sessionId", session.ID)
 
+	accessToken, err := usersService.signAccessToken(*user, session.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &jwt, nil
+	return &Token{
+		AccessToken:  *accessToken,
+		RefreshToken: *refreshToken,
+	}, nil
 }
 
-func (usersService *UsersService) hashPassword(ctx context.Context, password string) (*string, error) {
-	maxPasswordLength := 72
+func (usersService *UsersService) hashPassword(password string) (*string, error) {
+	maxPasswordLength := 1024
 
 	if len(password) > maxPasswordLength {
 		return nil, &InvalidArgumentError{msg: fmt.Sprintf("Password length must be less than or equal to %d", maxPasswordLength)}
 	}
 
-	var dest struct {
-		PasswordHash string
-	}
-
-	hashPasswordStmt := SELECT(StringExp(Func("crypt", String(password), Func("gen_salt", String("bf")))).AS("password_hash"))
-
-	err := hashPasswordStmt.QueryContext(ctx, usersService.db, &dest)
+	passwordHash, err := usersService.passwordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dest.PasswordHash, nil
+	return &passwordHash, nil
 }
 
-func (usersService *UsersService) validateEmail(ctx context.Context, email string) error {
+func (usersService *UsersService) validateEmail(ctx context.Context, email string, userId *uuid.UUID) error {
 	_, err := mail.ParseAddress(email)
 	if err != nil {
 		return err
@@ -339,7 +436,12 @@ func (usersService *UsersService) validateEmail(ctx context.Context, email strin
 		EmailExists bool
 	}
 
-	emailExistsStmt := SELECT(EXISTS(Users.SELECT(Users.ID).WHERE(Users.Email.EQ(String(email)))).AS("email_exists"))
+	condition := LOWER(Users.Email).EQ(LOWER(String(email)))
+	if userId != nil {
+		condition = condition.AND(Users.ID.NOT_EQ(UUID(*userId)))
+	}
+
+	emailExistsStmt := SELECT(EXISTS(Users.SELECT(Users.ID).WHERE(condition)).AS("email_exists"))
 
 	if err = emailExistsStmt.QueryContext(ctx, usersService.db, &emailExistsDest); err != nil {
 		return err
@@ -352,12 +454,25 @@ func (usersService *UsersService) validateEmail(ctx context.Context, email strin
 	return nil
 }
 
-func (usersService *UsersService) validateUsername(ctx context.Context, username string) error {
+func (usersService *UsersService) validateUsername(ctx context.Context, username string, userId *uuid.UUID) error {
+	if err := usersService.validateUsernameFormat(username); err != nil {
+		return err
+	}
+
+	if err := usersService.checkUsernameCooldown(ctx, username, userId); err != nil {
+		return err
+	}
+
 	var usernameExistsDest struct {
 		UsernameExists bool
 	}
 
-	usernameExistsStmt := SELECT(EXISTS(Users.SELECT(Users.ID).WHERE(Users.Username.EQ(String(username)))).AS("username_exists"))
+	condition := LOWER(Users.Username).EQ(LOWER(String(username)))
+	if userId != nil {
+		condition = condition.AND(Users.ID.NOT_EQ(UUID(*userId)))
+	}
+
+	usernameExistsStmt := SELECT(EXISTS(Users.SELECT(Users.ID).WHERE(condition)).AS("username_exists"))
 
 	err := usernameExistsStmt.QueryContext(ctx, usersService.db, &usernameExistsDest)
 	if err != nil {
@@ -371,6 +486,28 @@ func (usersService *UsersService) validateUsername(ctx context.Context, username
 	return nil
 }
 
+func (usersService *UsersService) validateUsernameFormat(username string) error {
+	if len(username) < minUsernameLength || len(username) > maxUsernameLength {
+		return &InvalidArgumentError{msg: fmt.Sprintf("Username length must be between %d and %d", minUsernameLength, maxUsernameLength)}
+	}
+
+	if usernameInvalidCharacters.MatchString(username) {
+		return &InvalidArgumentError{msg: "Username can only contain letters, digits, hyphens and underscores"}
+	}
+
+	if usersService.isReservedUsername(username) {
+		return &InvalidArgumentError{msg: fmt.Sprintf("Username %s is reserved", username)}
+	}
+
+	return nil
+}
+
+func (usersService *UsersService) isReservedUsername(username string) bool {
+	return slices.ContainsFunc(usersService.config.ReservedUsernames, func(reservedUsername string) bool {
+		return strings.EqualFold(strings.TrimSpace(reservedUsername), username)
+	})
+}
+
 func (usersService *UsersService) validateImage(image string) error {
 	_, err := url.ParseRequestURI(image)
 	if err != nil {
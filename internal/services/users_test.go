@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateUsernameFormat(t *testing.T) {
+	usersService := &UsersService{
+		config: UsersServiceConfig{
+			ReservedUsernames: []string{"admin", " settings"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		username string
+		valid    bool
+	}{
+		{name: "letters", username: "alice", valid: true},
+		{name: "digits, hyphens and underscores", username: "Alice_Smith-42", valid: true},
+		{name: "shortest", username: "bob", valid: true},
+		{name: "longest", username: strings.Repeat("a", maxUsernameLength), valid: true},
+		{name: "too short", username: "al"},
+		{name: "too long", username: strings.Repeat("a", maxUsernameLength+1)},
+		{name: "space", username: "alice smith"},
+		{name: "dot", username: "alice.smith"},
+		{name: "non-ASCII letter", username: "alicé"},
+		{name: "reserved", username: "admin"},
+		{name: "reserved ignoring case", username: "Admin"},
+		{name: "reserved with spaces in the configuration", username: "settings"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := usersService.validateUsernameFormat(testCase.username)
+
+			if testCase.valid {
+				if err != nil {
+					t.Errorf("got error %v, expected %s to be valid", err, testCase.username)
+				}
+				return
+			}
+
+			var invalidArgumentError *InvalidArgumentError
+			if !errors.As(err, &invalidArgumentError) {
+				t.Errorf("got error %v, expected an InvalidArgumentError for %s", err, testCase.username)
+			}
+		})
+	}
+}
+
+func TestUpdateUserKeepsUnsetFields(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	bio := "I write about databases."
+	image := "https://example.com/avatar.png"
+
+	user, err := usersService.UpdateUser(ctx, user.ID, UpdateUser{Bio: &bio, Image: &image})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	email := uniqueName(t, "renamed-") + "@example.com"
+	username := uniqueName(t, "renamed-")
+
+	user, err = usersService.UpdateUser(ctx, user.ID, UpdateUser{Email: &email, Username: &username})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Email != email || user.Username != username {
+		t.Errorf("got email %s and username %s, expected %s and %s", user.Email, user.Username, email, username)
+	}
+
+	if user.Bio == nil || *user.Bio != bio {
+		t.Errorf("got bio %v, expected %s", user.Bio, bio)
+	}
+
+	if user.Image == nil || *user.Image != image {
+		t.Errorf("got image %v, expected %s", user.Image, image)
+	}
+}
+
+func TestForcePasswordResetRevokesCredentials(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	sessionToken, err := usersService.GetToken(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, personalAccessToken, err := usersService.CreatePersonalAccessToken(ctx, user.ID, NewCreatePersonalAccessToken("ci", []string{ScopeArticlesWrite}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = usersService.ForcePasswordReset(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if checkTestPassword(t, usersService, user.Username, testPassword) {
+		t.Error("expected the password to be replaced")
+	}
+
+	if _, _, err = usersService.RefreshSession(ctx, sessionToken.RefreshToken); err == nil {
+		t.Error("expected the sessions to be revoked")
+	}
+
+	if _, _, err = usersService.GetUserByPersonalAccessToken(ctx, *personalAccessToken); err == nil {
+		t.Error("expected the personal access tokens to be revoked")
+	}
+}
+
+func TestRequestAccountDeletion(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	_, personalAccessToken, err := usersService.CreatePersonalAccessToken(ctx, user.ID, NewCreatePersonalAccessToken("ci", []string{ScopeArticlesWrite}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = usersService.RequestAccountDeletion(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = usersService.GetUserByPersonalAccessToken(ctx, *personalAccessToken); err == nil {
+		t.Error("expected the personal access tokens to be revoked")
+	}
+
+	user, err = usersService.GetUserById(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.DeletionRequestedAt == nil {
+		t.Fatal("expected the deletion to be requested")
+	}
+
+	if _, err = usersService.GetToken(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err = usersService.GetUserById(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.DeletionRequestedAt != nil {
+		t.Error("expected logging in to cancel the deletion")
+	}
+}
+
+func TestRegisterUserIgnoresCase(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	username := uniqueName(t, "Alice-")
+
+	user, err := usersService.RegisterUser(ctx, NewRegisterUser(username+"@Example.com", username, testPassword))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var alreadyExistsError *AlreadyExistsError
+
+	if _, err = usersService.RegisterUser(ctx, NewRegisterUser(uniqueName(t, "other-")+"@example.com", strings.ToLower(username), testPassword)); !errors.As(err, &alreadyExistsError) {
+		t.Errorf("got error %v registering the username in lower case, expected an AlreadyExistsError", err)
+	}
+
+	if _, err = usersService.RegisterUser(ctx, NewRegisterUser(strings.ToUpper(user.Email), uniqueName(t, "other-"), testPassword)); !errors.As(err, &alreadyExistsError) {
+		t.Errorf("got error %v registering the email in upper case, expected an AlreadyExistsError", err)
+	}
+
+	foundUser, err := usersService.GetUserByUsername(ctx, strings.ToUpper(username))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if foundUser.ID != user.ID {
+		t.Errorf("got user %s, expected %s", foundUser.ID, user.ID)
+	}
+}
+
+func TestUpdateUserIsPrivate(t *testing.T) {
+	db := newTestDB(t)
+	usersService := newTestUsersService(t, db)
+
+	ctx := context.Background()
+
+	user := createTestUser(t, usersService)
+
+	image := "https://example.com/avatar.png"
+
+	user, err := usersService.UpdateUser(ctx, user.ID, UpdateUser{Image: &image})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	isPrivate := true
+
+	user, err = usersService.UpdateUser(ctx, user.ID, UpdateUser{IsPrivate: &isPrivate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !user.IsPrivate {
+		t.Error("expected the user to be private")
+	}
+
+	if user.Image == nil || *user.Image != image {
+		t.Errorf("got image %v, expected %s", user.Image, image)
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_session_refresh_token;
+
+DROP TABLE IF EXISTS user_session;
\ No newline at end of file
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS user_session (
+    id UUID CONSTRAINT user_session_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT user_session_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT user_session_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_session_user_id_idx ON user_session (user_id);
+
+CREATE TABLE IF NOT EXISTS user_session_refresh_token (
+    id UUID CONSTRAINT user_session_refresh_token_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    session_id UUID CONSTRAINT user_session_refresh_token_session_id_fk REFERENCES user_session (id) ON DELETE CASCADE,
+    token_hash TEXT CONSTRAINT user_session_refresh_token_token_hash_uk UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE CONSTRAINT user_session_refresh_token_expires_at_nn NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT user_session_refresh_token_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
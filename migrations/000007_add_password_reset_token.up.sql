@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS password_reset_token (
+    id UUID CONSTRAINT password_reset_token_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT password_reset_token_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT CONSTRAINT password_reset_token_token_hash_uk UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE CONSTRAINT password_reset_token_expires_at_nn NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT password_reset_token_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
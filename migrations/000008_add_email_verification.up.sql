@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS email_verification_token (
+    id UUID CONSTRAINT email_verification_token_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT email_verification_token_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    email TEXT CONSTRAINT email_verification_token_email_nn NOT NULL,
+    token_hash TEXT CONSTRAINT email_verification_token_token_hash_uk UNIQUE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE CONSTRAINT email_verification_token_expires_at_nn NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT email_verification_token_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
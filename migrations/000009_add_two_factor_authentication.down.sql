@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS login_challenge;
+
+DROP TABLE IF EXISTS user_recovery_code;
+
+DROP TABLE IF EXISTS user_totp;
\ No newline at end of file
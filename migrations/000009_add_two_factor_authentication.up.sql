@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS user_totp (
+    id UUID CONSTRAINT user_totp_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT user_totp_user_id_uk UNIQUE CONSTRAINT user_totp_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    encrypted_secret TEXT CONSTRAINT user_totp_encrypted_secret_nn NOT NULL,
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT user_totp_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_code (
+    id UUID CONSTRAINT user_recovery_code_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT user_recovery_code_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    code_hash TEXT CONSTRAINT user_recovery_code_code_hash_nn NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT user_recovery_code_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS user_recovery_code_user_id_idx ON user_recovery_code (user_id);
+
+CREATE TABLE IF NOT EXISTS login_challenge (
+    id UUID CONSTRAINT login_challenge_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT login_challenge_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    token_hash TEXT CONSTRAINT login_challenge_token_hash_uk UNIQUE NOT NULL,
+    failed_attempts INTEGER CONSTRAINT login_challenge_failed_attempts_df DEFAULT 0 NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE CONSTRAINT login_challenge_expires_at_nn NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT login_challenge_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
\ No newline at end of file
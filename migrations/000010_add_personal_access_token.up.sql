@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS personal_access_token (
+    id UUID CONSTRAINT personal_access_token_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT personal_access_token_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT CONSTRAINT personal_access_token_name_nn NOT NULL,
+    token_hash TEXT CONSTRAINT personal_access_token_token_hash_uk UNIQUE NOT NULL,
+    scopes TEXT CONSTRAINT personal_access_token_scopes_nn NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT personal_access_token_created_at_df DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT personal_access_token_user_id_name_uq UNIQUE (user_id, name)
+);
\ No newline at end of file
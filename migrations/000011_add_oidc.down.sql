@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_identity;
+
+DROP TABLE IF EXISTS oidc_login_state;
\ No newline at end of file
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS oidc_login_state (
+    id UUID CONSTRAINT oidc_login_state_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    state_hash TEXT CONSTRAINT oidc_login_state_state_hash_uk UNIQUE NOT NULL,
+    nonce TEXT CONSTRAINT oidc_login_state_nonce_nn NOT NULL,
+    code_verifier TEXT CONSTRAINT oidc_login_state_code_verifier_nn NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE CONSTRAINT oidc_login_state_expires_at_nn NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT oidc_login_state_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS user_identity (
+    id UUID CONSTRAINT user_identity_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT user_identity_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    issuer TEXT CONSTRAINT user_identity_issuer_nn NOT NULL,
+    subject TEXT CONSTRAINT user_identity_subject_nn NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT user_identity_created_at_df DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT user_identity_issuer_subject_uk UNIQUE (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS user_identity_user_id_idx ON user_identity (user_id);
\ No newline at end of file
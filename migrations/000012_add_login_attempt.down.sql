@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS account_lockout;
+
+DROP TABLE IF EXISTS login_attempt;
\ No newline at end of file
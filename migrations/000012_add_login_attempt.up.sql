@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS login_attempt (
+    id UUID CONSTRAINT login_attempt_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT login_attempt_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    email TEXT CONSTRAINT login_attempt_email_nn NOT NULL,
+    ip_address TEXT CONSTRAINT login_attempt_ip_address_nn NOT NULL,
+    succeeded BOOLEAN CONSTRAINT login_attempt_succeeded_nn NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT login_attempt_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS login_attempt_user_id_created_at_idx ON login_attempt (user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS login_attempt_ip_address_created_at_idx ON login_attempt (ip_address, created_at);
+
+CREATE TABLE IF NOT EXISTS account_lockout (
+    id UUID CONSTRAINT account_lockout_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT account_lockout_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    failed_attempts INTEGER CONSTRAINT account_lockout_failed_attempts_nn NOT NULL,
+    ip_address TEXT CONSTRAINT account_lockout_ip_address_nn NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE CONSTRAINT account_lockout_locked_until_nn NOT NULL,
+    unlocked_at TIMESTAMP WITH TIME ZONE,
+    unlocked_by UUID CONSTRAINT account_lockout_unlocked_by_fk REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT account_lockout_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS account_lockout_user_id_created_at_idx ON account_lockout (user_id, created_at);
\ No newline at end of file
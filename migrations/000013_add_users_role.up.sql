@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT CONSTRAINT users_role_df DEFAULT 'user' NOT NULL CONSTRAINT users_role_ck CHECK (role IN ('user', 'moderator', 'admin'));
\ No newline at end of file
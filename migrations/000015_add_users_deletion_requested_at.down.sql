@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN IF EXISTS deletion_requested_at;
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS users_lower_username_uk;
+DROP INDEX IF EXISTS users_lower_email_uk;
+
+ALTER TABLE users ADD CONSTRAINT users_email_uk UNIQUE (email);
\ No newline at end of file
@@ -0,0 +1,44 @@
+DO $$
+DECLARE
+    duplicate_emails TEXT;
+    duplicate_user RECORD;
+    suffix INTEGER;
+    new_username TEXT;
+BEGIN
+    SELECT string_agg(emails, '; ') INTO duplicate_emails
+    FROM (
+        SELECT string_agg(email, ', ' ORDER BY created_at, id) AS emails
+        FROM users
+        GROUP BY LOWER(email)
+        HAVING COUNT(*) > 1
+    ) AS duplicates;
+
+    IF duplicate_emails IS NOT NULL THEN
+        RAISE EXCEPTION 'Users with emails that differ only by case must be merged by hand first: %', duplicate_emails;
+    END IF;
+
+    FOR duplicate_user IN
+        SELECT id, username
+        FROM (
+            SELECT id, username, created_at, ROW_NUMBER() OVER (PARTITION BY LOWER(username) ORDER BY created_at, id) AS position
+            FROM users
+        ) AS usernames
+        WHERE position > 1
+        ORDER BY created_at, id
+    LOOP
+        suffix := 1;
+
+        LOOP
+            new_username := LEFT(duplicate_user.username, 31 - LENGTH(suffix::TEXT)) || '_' || suffix;
+            EXIT WHEN NOT EXISTS (SELECT 1 FROM users WHERE LOWER(username) = LOWER(new_username));
+            suffix := suffix + 1;
+        END LOOP;
+
+        UPDATE users SET username = new_username WHERE id = duplicate_user.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_uk;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_lower_email_uk ON users (LOWER(email));
+CREATE UNIQUE INDEX IF NOT EXISTS users_lower_username_uk ON users (LOWER(username));
\ No newline at end of file
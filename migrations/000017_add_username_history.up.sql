@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS username_history (
+    id UUID CONSTRAINT username_history_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID CONSTRAINT username_history_user_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    username TEXT CONSTRAINT username_history_username_nn NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT username_history_created_at_df DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS username_history_user_id_idx ON username_history (user_id);
+CREATE INDEX IF NOT EXISTS username_history_lower_username_created_at_idx ON username_history (LOWER(username), created_at);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS follow_followed_id_idx;
\ No newline at end of file
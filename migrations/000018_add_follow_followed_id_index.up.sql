@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS follow_followed_id_idx ON follow (followed_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS mute;
+
+DROP TABLE IF EXISTS block;
\ No newline at end of file
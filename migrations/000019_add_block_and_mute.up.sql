@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS block (
+    id UUID CONSTRAINT block_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    blocker_id UUID CONSTRAINT block_blocker_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    blocked_id UUID CONSTRAINT block_blocked_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT block_created_at_df DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT block_blocker_id_blocked_id_uk UNIQUE (blocker_id, blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS block_blocked_id_idx ON block (blocked_id);
+
+CREATE TABLE IF NOT EXISTS mute (
+    id UUID CONSTRAINT mute_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    muter_id UUID CONSTRAINT mute_muter_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    muted_id UUID CONSTRAINT mute_muted_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT mute_created_at_df DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT mute_muter_id_muted_id_uk UNIQUE (muter_id, muted_id)
+);
+
+CREATE INDEX IF NOT EXISTS mute_muted_id_idx ON mute (muted_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS follow_request;
+
+ALTER TABLE users DROP COLUMN IF EXISTS is_private;
\ No newline at end of file
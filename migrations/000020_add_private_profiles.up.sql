@@ -0,0 +1,11 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_private BOOLEAN CONSTRAINT users_is_private_df DEFAULT FALSE NOT NULL;
+
+CREATE TABLE IF NOT EXISTS follow_request (
+    id UUID CONSTRAINT follow_request_pk PRIMARY KEY DEFAULT gen_random_uuid (),
+    follower_id UUID CONSTRAINT follow_request_follower_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    followed_id UUID CONSTRAINT follow_request_followed_id_fk REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE CONSTRAINT follow_request_created_at_df DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT follow_request_follower_id_followed_id_uk UNIQUE (follower_id, followed_id)
+);
+
+CREATE INDEX IF NOT EXISTS follow_request_followed_id_idx ON follow_request (followed_id);
\ No newline at end of file
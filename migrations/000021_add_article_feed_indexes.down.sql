@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS article_created_at_id_idx;
+
+DROP INDEX IF EXISTS article_author_id_created_at_id_idx;
\ No newline at end of file
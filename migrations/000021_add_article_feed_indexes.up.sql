@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS article_author_id_created_at_id_idx ON article (author_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS article_created_at_id_idx ON article (created_at DESC, id DESC);
\ No newline at end of file
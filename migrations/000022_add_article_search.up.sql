@@ -0,0 +1,7 @@
+ALTER TABLE article ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('english', description), 'B') ||
+    setweight(to_tsvector('english', body), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS article_search_vector_idx ON article USING GIN (search_vector);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS article_article_tag_article_tag_id_idx;
+
+DROP INDEX IF EXISTS article_article_tag_article_id_idx;
\ No newline at end of file
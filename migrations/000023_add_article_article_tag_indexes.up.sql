@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS article_article_tag_article_id_idx ON article_article_tag (article_id);
+
+CREATE INDEX IF NOT EXISTS article_article_tag_article_tag_id_idx ON article_article_tag (article_tag_id);
+
+DELETE FROM article_tag WHERE NOT EXISTS (SELECT 1 FROM article_article_tag WHERE article_article_tag.article_tag_id = article_tag.id);
\ No newline at end of file